@@ -0,0 +1,20 @@
+package oid
+
+import "context"
+
+// contextKey is the type of the context key used by WithObjectID, unexported so
+// that it cannot collide with keys defined in other packages.
+type contextKey struct{}
+
+// WithObjectID returns a copy of ctx carrying id, such as the id of the entity
+// a request is acting on. Retrieve it with ObjectIDFromContext.
+func WithObjectID(ctx context.Context, id ObjectID) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ObjectIDFromContext returns the id stored in ctx by WithObjectID, with ok
+// false if there is none.
+func ObjectIDFromContext(ctx context.Context) (id ObjectID, ok bool) {
+	id, ok = ctx.Value(contextKey{}).(ObjectID)
+	return id, ok
+}
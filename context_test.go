@@ -0,0 +1,45 @@
+package oid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("set", func(t *testing.T) {
+		ctx := WithObjectID(context.Background(), id)
+		out, ok := ObjectIDFromContext(ctx)
+		if !ok {
+			t.Fatalf("expected an id in the context")
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		other := NewObjectID()
+		ctx := WithObjectID(WithObjectID(context.Background(), id), other)
+		if out, _ := ObjectIDFromContext(ctx); out != other {
+			t.Fatalf("expected %v, got %v", other, out)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if out, ok := ObjectIDFromContext(context.Background()); ok || out != "" {
+			t.Fatalf("expected no id, got %v", out)
+		}
+	})
+
+	t.Run("string_key", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), "oid", id)
+		if _, ok := ObjectIDFromContext(ctx); ok {
+			t.Fatalf("expected a string key not to be found")
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package oid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bytes returns a copy of the 12 raw bytes of the id, for building composite
+// keys or hashing. It returns nil for the empty id and for any other invalid
+// id. See FromBytes for the reverse.
+func (id ObjectID) Bytes() []byte {
+	if len(id) != 12 {
+		return nil
+	}
+	return []byte(id)
+}
+
+// FromBytes is the strict reverse of Bytes: it returns the ObjectID held in b,
+// copying it, and returns an error for any length other than 12. Unlike
+// ObjectIDFromBytes, a nil or empty slice is an error rather than the empty id.
+func FromBytes(b []byte) (ObjectID, error) {
+	if len(b) != 12 {
+		return "", fmt.Errorf("invalid ObjectID length: expected 12 bytes, got %d", len(b))
+	}
+	return ObjectIDFromBytes(b)
+}
+
+// ObjectIDFromBytes returns the ObjectID held in b, copying it so later changes
+// to b do not affect the id. A nil or empty slice is treated as unset and
+// yields the empty ObjectID with a nil error, matching the handling of empty
+// JSON values. Any other slice that is not 12 bytes long returns an error.
+func ObjectIDFromBytes(b []byte) (ObjectID, error) {
+	switch len(b) {
+	case 0:
+		return "", nil
+	case 12:
+		return ObjectID(b), nil
+	}
+	return "", fmt.Errorf("invalid ObjectID length: expected 12 bytes, got %d", len(b))
+}
+
+// SplitRawObjectIDs splits b, a run of raw 12-byte ids concatenated without
+// delimiters, into ObjectIDs. It returns an error if the length of b is not a
+// multiple of 12. An empty b returns no ids.
+func SplitRawObjectIDs(b []byte) ([]ObjectID, error) {
+	if len(b)%12 != 0 {
+		return nil, fmt.Errorf("invalid length for raw ObjectIDs: %d is not a multiple of 12", len(b))
+	}
+	ids := make([]ObjectID, len(b)/12)
+	for i := range ids {
+		ids[i] = ObjectID(b[i*12 : i*12+12])
+	}
+	return ids, nil
+}
+
+// TaggedBytes returns the 12 raw bytes of the id prefixed with a 1-byte type
+// tag, 13 bytes in all, for binary records that mix several kinds of id. It
+// returns an error for an invalid id. See ObjectIDFromTaggedBytes.
+func (id ObjectID) TaggedBytes(tag byte) ([]byte, error) {
+	if len(id) != 12 {
+		return nil, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return append([]byte{tag}, id...), nil
+}
+
+// ObjectIDFromTaggedBytes reverses TaggedBytes, returning the tag and the id.
+// It returns an error if b is not 13 bytes long.
+func ObjectIDFromTaggedBytes(b []byte) (byte, ObjectID, error) {
+	if len(b) != 13 {
+		return 0, "", fmt.Errorf("invalid tagged ObjectID length: expected 13 bytes, got %d", len(b))
+	}
+	return b[0], ObjectID(b[1:]), nil
+}
+
+// ReadFramedObjectID reads an id framed as a uvarint length followed by that
+// many bytes, as written by protobuf-style length-prefixed streams. A length
+// other than 12 returns an error without reading the payload. Reads never go
+// past the end of the frame, so r can be read again for the next one.
+func ReadFramedObjectID(r io.Reader) (ObjectID, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = byteReader{r}
+	}
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return "", fmt.Errorf("could not read frame length: %w", err)
+	}
+	if n != 12 {
+		return "", fmt.Errorf("invalid frame length: expected 12 bytes, got %d", n)
+	}
+
+	b := make([]byte, 12)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", fmt.Errorf("could not read frame: %w", err)
+	}
+	return ObjectID(b), nil
+}
+
+// byteReader reads one byte at a time from a reader that is not already an
+// io.ByteReader, so no bytes beyond the varint are consumed.
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(r.Reader, b[:])
+	return b[0], err
+}
+
+// decimalWidth is the number of digits in the largest 96-bit value,
+// 79228162514264337593543950335.
+const decimalWidth = 29
+
+// DecimalString returns the id as a 96-bit big-endian unsigned integer in
+// decimal, zero padded to 29 digits so that the lexicographic order of the
+// strings matches the order of the ids. An invalid id returns an empty string.
+// See ObjectIDFromDecimalString.
+func (id ObjectID) DecimalString() string {
+	if !id.Valid() {
+		return ""
+	}
+	d := new(big.Int).SetBytes([]byte(id)).String()
+	return strings.Repeat("0", decimalWidth-len(d)) + d
+}
+
+// ObjectIDFromDecimalString reverses DecimalString. s must be exactly 29
+// decimal digits holding a value that fits in 12 bytes.
+func ObjectIDFromDecimalString(s string) (ObjectID, error) {
+	if len(s) != decimalWidth || strings.Trim(s, "0123456789") != "" {
+		return "", fmt.Errorf("invalid input to ObjectIDFromDecimalString: %q", s)
+	}
+	n, _ := new(big.Int).SetString(s, 10)
+	if n.BitLen() > 96 {
+		return "", fmt.Errorf("invalid input to ObjectIDFromDecimalString: %q", s)
+	}
+	var b [12]byte
+	return ObjectID(n.FillBytes(b[:])), nil
+}
+
+// base58Alphabet is the Bitcoin base58 alphabet, which leaves out 0, O, I and l
+// as they are easily confused.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58 returns the 12 raw bytes of the id in base58, a short form that is
+// easier to read aloud or type than hex. As usual for base58, each leading zero
+// byte is written as a leading '1'. An invalid id returns an empty string. See
+// ObjectIDFromBase58.
+func (id ObjectID) Base58() string {
+	if !id.Valid() {
+		return ""
+	}
+
+	var out []byte
+	n := new(big.Int).SetBytes([]byte(id))
+	radix, mod := big.NewInt(58), new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < len(id) && id[i] == 0; i++ {
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// ObjectIDFromBase58 reverses Base58. It returns an error if s contains a
+// character outside the base58 alphabet or does not decode to exactly 12 bytes.
+func ObjectIDFromBase58(s string) (ObjectID, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	n, radix := new(big.Int), big.NewInt(58)
+	for i := zeros; i < len(s); i++ {
+		d := strings.IndexByte(base58Alphabet, s[i])
+		if d < 0 {
+			return "", fmt.Errorf("invalid input to ObjectIDFromBase58: %q", s)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+
+	b := append(make([]byte, zeros), n.Bytes()...)
+	if len(b) != 12 {
+		return "", fmt.Errorf("invalid input to ObjectIDFromBase58: %q", s)
+	}
+	return ObjectID(b), nil
+}
+
+// ToTimeOrderedBytes lays the id out as 16 bytes for stores that standardize on
+// time-ordered, UUIDv7-like keys. The 4-byte timestamp occupies the high bytes,
+// followed by the remaining 8 bytes of the id and 4 bytes of zero padding, so
+// the lexicographic order of the result matches the time order of the ids.
+// An invalid id yields all zero bytes. See ObjectIDFromTimeOrderedBytes.
+func (id ObjectID) ToTimeOrderedBytes() [16]byte {
+	var b [16]byte
+	if len(id) != 12 {
+		return b
+	}
+	copy(b[:], id)
+	return b
+}
+
+// ObjectIDFromTimeOrderedBytes reverses ToTimeOrderedBytes. It returns an error
+// if the padding bytes are not zero, as the input then cannot have come from an
+// ObjectID.
+func ObjectIDFromTimeOrderedBytes(b [16]byte) (ObjectID, error) {
+	for _, p := range b[12:] {
+		if p != 0 {
+			return "", errors.New("time ordered bytes are not an ObjectID")
+		}
+	}
+	return ObjectID(b[:12]), nil
+}
+
+// ObjectIDFromUUIDPrefix returns the ObjectID held in the first 12 bytes of a
+// 16-byte id, for systems that embed an ObjectID at the start of a UUID sized
+// value. The trailing 4 bytes are discarded, so the conversion is lossy and
+// cannot be reversed.
+func ObjectIDFromUUIDPrefix(u [16]byte) ObjectID {
+	return ObjectID(u[:12])
+}
+
+// ToPrimitive returns the id as a driver ObjectID for use in raw driver calls,
+// copying the bytes directly. It returns an error for an invalid id.
+func (id ObjectID) ToPrimitive() (primitive.ObjectID, error) {
+	var p primitive.ObjectID
+	if len(id) != 12 {
+		return p, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	copy(p[:], id)
+	return p, nil
+}
+
+// FromPrimitive returns the ObjectID holding the bytes of the driver ObjectID p.
+func FromPrimitive(p primitive.ObjectID) ObjectID {
+	return ObjectID(p[:])
+}
+
+// MapKeysFromPrimitive rekeys a map of driver ObjectIDs, as produced when
+// aggregating driver results, into a map keyed by ObjectID. Values are kept as
+// they are.
+func MapKeysFromPrimitive[T any](m map[primitive.ObjectID]T) map[ObjectID]T {
+	out := make(map[ObjectID]T, len(m))
+	for k, v := range m {
+		out[FromPrimitive(k)] = v
+	}
+	return out
+}
+
+// PrimitiveSliceFromHexes parses a list of hex ids straight into driver
+// ObjectIDs, ready for use in an $in query. The first entry that fails to parse
+// returns an error naming its index.
+func PrimitiveSliceFromHexes(hexes []string) ([]primitive.ObjectID, error) {
+	out := make([]primitive.ObjectID, len(hexes))
+	for i, h := range hexes {
+		p, err := primitive.ObjectIDFromHex(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input at index %d: %q", i, h)
+		}
+		out[i] = p
+	}
+	return out, nil
+}
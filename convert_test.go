@@ -0,0 +1,542 @@
+package oid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBytes(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		b := id.Bytes()
+		if hex.EncodeToString(b) != testID {
+			t.Fatalf("expected %s, got %x", testID, b)
+		}
+		out, err := FromBytes(b)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("copies", func(t *testing.T) {
+		b := id.Bytes()
+		out, _ := FromBytes(b)
+		b[0] = 0
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+		id.Bytes()[0] = 0
+		if id.Hex() != testID {
+			t.Fatalf("expected %s, got %v", testID, id)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if b := ObjectID("").Bytes(); b != nil {
+			t.Fatalf("expected nil, got %x", b)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if b := ObjectID("123").Bytes(); b != nil {
+			t.Fatalf("expected nil, got %x", b)
+		}
+	})
+
+	t.Run("wrong_length", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			in       []byte
+			expected string
+		}{
+			{"nil", nil, "invalid ObjectID length: expected 12 bytes, got 0"},
+			{"empty", []byte{}, "invalid ObjectID length: expected 12 bytes, got 0"},
+			{"short", []byte("123"), "invalid ObjectID length: expected 12 bytes, got 3"},
+			{"long", []byte("0123456789abc"), "invalid ObjectID length: expected 12 bytes, got 13"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				out, err := FromBytes(tt.in)
+				if err == nil || err.Error() != tt.expected {
+					t.Fatalf("expected %s, got %v", tt.expected, err)
+				}
+				if out != "" {
+					t.Fatalf("expected empty id, got %v", out)
+				}
+			})
+		}
+	})
+}
+
+func TestObjectIDFromBytes(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	tests := []struct {
+		name string
+		in   []byte
+		out  ObjectID
+		err  string
+	}{
+		{"nil", nil, "", ""},
+		{"empty", []byte{}, "", ""},
+		{"valid", []byte(id), id, ""},
+		{"short", []byte("123"), "", "invalid ObjectID length: expected 12 bytes, got 3"},
+		{"long", []byte("0123456789abc"), "", "invalid ObjectID length: expected 12 bytes, got 13"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ObjectIDFromBytes(tt.in)
+			if tt.err == "" && err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Fatalf("expected %s, got %v", tt.err, err)
+			}
+			if out != tt.out {
+				t.Fatalf("expected %v, got %v", tt.out, out)
+			}
+		})
+	}
+
+	t.Run("copies", func(t *testing.T) {
+		b := []byte(id)
+		out, _ := ObjectIDFromBytes(b)
+		b[0] = 0
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+}
+
+func TestSplitRawObjectIDs(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	other := NewObjectID()
+
+	t.Run("multiple", func(t *testing.T) {
+		ids, err := SplitRawObjectIDs([]byte(string(id) + string(other) + string(id)))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(ids) != 3 || ids[0] != id || ids[1] != other || ids[2] != id {
+			t.Fatalf("expected [%v %v %v], got %v", id, other, id, ids)
+		}
+	})
+
+	t.Run("single", func(t *testing.T) {
+		ids, err := SplitRawObjectIDs([]byte(id))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(ids) != 1 || ids[0] != id {
+			t.Fatalf("expected [%v], got %v", id, ids)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		ids, err := SplitRawObjectIDs(nil)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(ids) != 0 {
+			t.Fatalf("expected no ids, got %v", ids)
+		}
+	})
+
+	t.Run("not_multiple", func(t *testing.T) {
+		expected := "invalid length for raw ObjectIDs: 13 is not a multiple of 12"
+		ids, err := SplitRawObjectIDs([]byte(string(id) + "x"))
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+		if ids != nil {
+			t.Fatalf("expected nil, got %v", ids)
+		}
+	})
+}
+
+func TestTaggedBytes(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		b, err := id.TaggedBytes(7)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(b) != 13 || b[0] != 7 || string(b[1:]) != string(id) {
+			t.Fatalf("expected 07%s, got %x", testID, b)
+		}
+
+		tag, out, err := ObjectIDFromTaggedBytes(b)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if tag != 7 || out != id {
+			t.Fatalf("expected 7 and %v, got %d and %v", id, tag, out)
+		}
+
+		b[1] = 0
+		if out != id {
+			t.Fatalf("expected %v to be unaffected by changes to the input, got %v", id, out)
+		}
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if _, err := ObjectID("123").TaggedBytes(7); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("wrong_length", func(t *testing.T) {
+		expected := "invalid tagged ObjectID length: expected 13 bytes, got 12"
+		if _, _, err := ObjectIDFromTaggedBytes([]byte(id)); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestReadFramedObjectID(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.Write(binary.AppendUvarint(nil, 12))
+		buf.WriteString(string(id))
+		buf.WriteString("rest")
+
+		out, err := ReadFramedObjectID(&buf)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+		if buf.String() != "rest" {
+			t.Fatalf("expected rest to be left unread, got %q", buf.String())
+		}
+	})
+
+	t.Run("plain_reader", func(t *testing.T) {
+		r := io.MultiReader(bytes.NewReader([]byte{12}), strings.NewReader(string(id)))
+		out, err := ReadFramedObjectID(r)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("wrong_length", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.Write(binary.AppendUvarint(nil, 300))
+		buf.WriteString(string(id))
+
+		expected := "invalid frame length: expected 12 bytes, got 300"
+		_, err := ReadFramedObjectID(&buf)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		buf := bytes.NewBuffer(append([]byte{12}, id[:6]...))
+		_, err := ReadFramedObjectID(buf)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+		}
+	})
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		dec  string
+	}{
+		{"zero", "000000000000000000000000", "00000000000000000000000000000"},
+		{"one", "000000000000000000000001", "00000000000000000000000000001"},
+		{"valid", testID, "28916825314940904717453528467"},
+		{"max", "ffffffffffffffffffffffff", "79228162514264337593543950335"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ObjectIDHex(tt.hex)
+			if err != nil {
+				t.Fatalf("could not make objectId %v", err)
+			}
+			if id.DecimalString() != tt.dec {
+				t.Fatalf("expected %s, got %s", tt.dec, id.DecimalString())
+			}
+
+			out, err := ObjectIDFromDecimalString(tt.dec)
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != id {
+				t.Fatalf("expected %v, got %v", id, out)
+			}
+		})
+	}
+
+	t.Run("ordering", func(t *testing.T) {
+		lo, _ := ObjectIDHex("00000000000000000000ffff")
+		hi, _ := ObjectIDHex("000000000000000000010000")
+		if lo.DecimalString() >= hi.DecimalString() {
+			t.Fatalf("expected %s < %s", lo.DecimalString(), hi.DecimalString())
+		}
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		if ObjectID("123").DecimalString() != "" {
+			t.Fatalf("expected empty string, got %s", ObjectID("123").DecimalString())
+		}
+	})
+
+	t.Run("invalid_input", func(t *testing.T) {
+		for _, s := range []string{
+			"",
+			"1",
+			"79228162514264337593543950336",
+			"99999999999999999999999999999",
+			"0000000000000000000000000000a",
+			"-0000000000000000000000000001",
+		} {
+			expected := fmt.Sprintf("invalid input to ObjectIDFromDecimalString: %q", s)
+			_, err := ObjectIDFromDecimalString(s)
+			if err == nil || err.Error() != expected {
+				t.Fatalf("expected %s, got %v", expected, err)
+			}
+		}
+	})
+}
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		b58  string
+	}{
+		{"zero", "000000000000000000000000", "111111111111"},
+		{"leading_zero", "00000000000000000000003a", "1111111111121"},
+		{"valid", testID, "2mGRNztMk2HTfsS5x"},
+		{"max", "ffffffffffffffffffffffff", "5qCHTcgbQwpvYZQ9c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ObjectIDHex(tt.hex)
+			if err != nil {
+				t.Fatalf("could not make objectId %v", err)
+			}
+			if id.Base58() != tt.b58 {
+				t.Fatalf("expected %s, got %s", tt.b58, id.Base58())
+			}
+
+			out, err := ObjectIDFromBase58(tt.b58)
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != id {
+				t.Fatalf("expected %v, got %v", id, out)
+			}
+		})
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			id := NewObjectID()
+			out, err := ObjectIDFromBase58(id.Base58())
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != id {
+				t.Fatalf("expected %v, got %v", id, out)
+			}
+		}
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		if ObjectID("123").Base58() != "" {
+			t.Fatalf("expected empty string, got %s", ObjectID("123").Base58())
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, s := range []string{
+			"",
+			"abc",
+			"5qCHTcgbQwpvYZQ9d",
+			"2mGRNztMk2HTfsS50",
+			"12mGRNztMk2HTfsS5x",
+		} {
+			expected := fmt.Sprintf("invalid input to ObjectIDFromBase58: %q", s)
+			if _, err := ObjectIDFromBase58(s); err == nil || err.Error() != expected {
+				t.Fatalf("expected %s, got %v", expected, err)
+			}
+		}
+	})
+}
+
+func TestPrimitive(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		p, err := id.ToPrimitive()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if p.Hex() != testID {
+			t.Fatalf("expected %s, got %s", testID, p.Hex())
+		}
+		if out := FromPrimitive(p); out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		p, err := ObjectID("123").ToPrimitive()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+		if !p.IsZero() {
+			t.Fatalf("expected zero ObjectID, got %v", p)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		if out := FromPrimitive(primitive.NilObjectID); !out.IsZero() || !out.Valid() {
+			t.Fatalf("expected a valid zero id, got %v", out)
+		}
+	})
+}
+
+func TestTimeOrderedBytes(t *testing.T) {
+	t.Run("round_trip", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		out, err := ObjectIDFromTimeOrderedBytes(id.ToTimeOrderedBytes())
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("ordering", func(t *testing.T) {
+		now := time.Now()
+		older, _ := ObjectIDHex(primitive.NewObjectIDFromTimestamp(now.Add(-time.Hour)).Hex())
+		newer, _ := ObjectIDHex(primitive.NewObjectIDFromTimestamp(now).Hex())
+
+		a, b := older.ToTimeOrderedBytes(), newer.ToTimeOrderedBytes()
+		if bytes.Compare(a[:], b[:]) >= 0 {
+			t.Fatalf("expected %x to sort before %x", a, b)
+		}
+	})
+
+	t.Run("invalid_padding", func(t *testing.T) {
+		var b [16]byte
+		b[15] = 1
+		expected := "time ordered bytes are not an ObjectID"
+		if _, err := ObjectIDFromTimeOrderedBytes(b); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestObjectIDFromUUIDPrefix(t *testing.T) {
+	var u [16]byte
+	for i := range u {
+		u[i] = byte(i + 1)
+	}
+
+	id := ObjectIDFromUUIDPrefix(u)
+	if !id.Valid() {
+		t.Fatalf("expected valid, got %v", id)
+	}
+	if !bytes.Equal([]byte(id), u[:12]) {
+		t.Fatalf("expected %x, got %x", u[:12], []byte(id))
+	}
+}
+
+func TestMapKeysFromPrimitive(t *testing.T) {
+	a, _ := primitive.ObjectIDFromHex(testID)
+	b := primitive.NewObjectID()
+
+	out := MapKeysFromPrimitive(map[primitive.ObjectID]int{a: 1, b: 2})
+	if len(out) != 2 {
+		t.Fatalf("expected 2 entries, got %v", out)
+	}
+
+	id, _ := ObjectIDHex(testID)
+	if out[id] != 1 {
+		t.Fatalf("expected 1, got %d", out[id])
+	}
+
+	other, _ := ObjectIDHex(b.Hex())
+	if out[other] != 2 {
+		t.Fatalf("expected 2, got %d", out[other])
+	}
+}
+
+func TestPrimitiveSliceFromHexes(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		other := "5d6f6ff1646327ce31968d94"
+		out, err := PrimitiveSliceFromHexes([]string{testID, other})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(out) != 2 || out[0].Hex() != testID || out[1].Hex() != other {
+			t.Fatalf("expected [%s %s], got %v", testID, other, out)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		out, err := PrimitiveSliceFromHexes(nil)
+		if err != nil || len(out) != 0 {
+			t.Fatalf("expected empty slice and nil, got %v and %v", out, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := `invalid input at index 1: "1234"`
+		if _, err := PrimitiveSliceFromHexes([]string{testID, "1234", "zz"}); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package oid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+)
+
+// processStart is recorded when the package is initialised and stands in for
+// the process start time when deriving counter seeds.
+var processStart = time.Now()
+
+// Generator produces ObjectIDs following the MongoDB layout: a 4-byte timestamp
+// taken from NowFunc, a 5-byte random value fixed for the lifetime of the
+// generator and a 3-byte counter incremented for every id. Its behaviour can be
+// adjusted with Options. A Generator is safe for concurrent use.
+type Generator struct {
+	mu      sync.Mutex
+	random  [5]byte
+	counter uint32
+
+	datacenter    byte
+	hasDatacenter bool
+
+	created time.Time
+}
+
+// Option configures a Generator created by NewGenerator.
+type Option func(*Generator)
+
+// NewGenerator returns a Generator with a random 5-byte value and a random
+// counter start, adjusted by opts.
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{created: NowFunc()}
+
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("cannot initialize objectid generator: %v", err))
+	}
+	copy(g.random[:], b[:5])
+	g.counter = uint32(b[5])<<16 | uint32(b[6])<<8 | uint32(b[7])
+
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// WithDerivedCounterStart starts the counter at CounterSeedFromHost instead of
+// a random value, so that a restarted process begins in a different counter
+// region than its predecessor.
+func WithDerivedCounterStart() Option {
+	return func(g *Generator) {
+		g.counter = CounterSeedFromHost()
+	}
+}
+
+// WithDatacenterByte fixes the first byte of the random value to b so that ids
+// carry the datacenter they were generated in. The remaining 4 random bytes
+// stay random, trading one byte of randomness for origin tagging.
+func WithDatacenterByte(b byte) Option {
+	return func(g *Generator) {
+		g.datacenter = b
+		g.hasDatacenter = true
+		g.random[0] = b
+	}
+}
+
+// New returns a new ObjectID.
+func (g *Generator) New() ObjectID {
+	g.mu.Lock()
+	g.counter = (g.counter + 1) & 0xffffff
+	counter := g.counter
+	g.mu.Unlock()
+
+	var b [12]byte
+	binary.BigEndian.PutUint32(b[0:4], uint32(NowFunc().Unix()))
+	copy(b[4:9], g.random[:])
+	b[9] = byte(counter >> 16)
+	b[10] = byte(counter >> 8)
+	b[11] = byte(counter)
+	return ObjectID(b[:])
+}
+
+// Validate returns an error unless id could plausibly have been produced by a
+// generator configured like g: it must be a valid ObjectID, carry the
+// datacenter byte set with WithDatacenterByte, if any, and have a timestamp no
+// earlier than the second g was created in.
+func (g *Generator) Validate(id ObjectID) error {
+	if !id.Valid() {
+		return fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	if g.hasDatacenter && id[4] != g.datacenter {
+		return fmt.Errorf("%s has datacenter byte %02x, expected %02x", id.String(), id[4], g.datacenter)
+	}
+	if created := g.created.Truncate(time.Second); id.Time().Before(created) {
+		return fmt.Errorf("%s was created at %s, before its generator at %s", id.String(), id.Time().UTC().Format(time.RFC3339), created.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// HybridGenerator produces ObjectIDs that are strictly increasing for the
+// lifetime of the generator, even if the clock steps backward. It remembers the
+// last timestamp it used; while NowFunc is behind it, ids keep that timestamp
+// and only the counter advances. The counter restarts at zero whenever the
+// timestamp moves forward, and if it runs out within one second the timestamp
+// is advanced by a second ahead of the clock.
+//
+// The embedded times can therefore drift ahead of the wall clock by the size of
+// a backward step, plus a second for every 2^24 ids generated within a single
+// second, until the clock catches up. A HybridGenerator is safe for concurrent
+// use.
+type HybridGenerator struct {
+	mu      sync.Mutex
+	random  [5]byte
+	last    uint32
+	counter uint32
+}
+
+// NewHybridGenerator returns a HybridGenerator with a random 5-byte value.
+func NewHybridGenerator() *HybridGenerator {
+	g := &HybridGenerator{}
+	if _, err := rand.Read(g.random[:]); err != nil {
+		panic(fmt.Sprintf("cannot initialize objectid generator: %v", err))
+	}
+	return g
+}
+
+// New returns a new ObjectID greater than every id previously returned by g.
+func (g *HybridGenerator) New() ObjectID {
+	now := uint32(NowFunc().Unix())
+
+	g.mu.Lock()
+	if now > g.last {
+		g.last = now
+		g.counter = 0
+	} else if g.counter++; g.counter > 0xffffff {
+		g.last++
+		g.counter = 0
+	}
+	ts, counter := g.last, g.counter
+	g.mu.Unlock()
+
+	var b [12]byte
+	binary.BigEndian.PutUint32(b[0:4], ts)
+	copy(b[4:9], g.random[:])
+	b[9] = byte(counter >> 16)
+	b[10] = byte(counter >> 8)
+	b[11] = byte(counter)
+	return ObjectID(b[:])
+}
+
+// CounterSeedFromHost derives a 24-bit counter start from the hostname, the
+// process id and the process start time. The seed is a hash, so different
+// processes only probably land in different counter regions; it narrows the
+// window for collisions after a restart rather than ruling them out.
+func CounterSeedFromHost() uint32 {
+	host, _ := os.Hostname()
+	return counterSeed(host, os.Getpid(), processStart)
+}
+
+func counterSeed(host string, pid int, start time.Time) uint32 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d:%d", host, pid, start.UnixNano())
+	return h.Sum32() & 0xffffff
+}
+
+// StressTestUniqueness generates n ids with NewObjectID and returns how many of
+// them collided with an id generated earlier in the run. With a 5-byte random
+// value and a 3-byte counter collisions should never happen, so any non-zero
+// result points at a bug in the generator.
+func StressTestUniqueness(n int) (collisions int) {
+	seen := make(map[ObjectID]struct{}, n)
+	for i := 0; i < n; i++ {
+		id := NewObjectID()
+		if _, ok := seen[id]; ok {
+			collisions++
+			continue
+		}
+		seen[id] = struct{}{}
+	}
+	return collisions
+}
@@ -0,0 +1,216 @@
+package oid
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGenerator(t *testing.T) {
+	setNow(t, time.Unix(testIDSecs, 0))
+	g := NewGenerator()
+
+	a, b := g.New(), g.New()
+	if !a.Valid() || !b.Valid() {
+		t.Fatalf("expected valid ids, got %v and %v", a, b)
+	}
+	if a == b {
+		t.Fatalf("expected distinct ids, got %v twice", a)
+	}
+	if a.Time() != time.Unix(testIDSecs, 0) {
+		t.Fatalf("expected time %v, got %v", time.Unix(testIDSecs, 0), a.Time())
+	}
+	if a[4:9] != b[4:9] {
+		t.Fatalf("expected shared random value, got %x and %x", a[4:9], b[4:9])
+	}
+	if b.Counter() != (a.Counter()+1)&0xffffff {
+		t.Fatalf("expected counter %d, got %d", a.Counter()+1, b.Counter())
+	}
+}
+
+func TestHybridGenerator(t *testing.T) {
+	now := time.Unix(testIDSecs, 0)
+	NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { NowFunc = time.Now })
+
+	g := NewHybridGenerator()
+	prev := g.New()
+	next := func() ObjectID {
+		t.Helper()
+		id := g.New()
+		if id <= prev {
+			t.Fatalf("expected %v to be greater than %v", id, prev)
+		}
+		prev = id
+		return id
+	}
+
+	next()
+	now = now.Add(time.Second)
+	next()
+
+	// the clock steps back a minute: the last timestamp is reused
+	now = now.Add(-time.Minute)
+	for i := 0; i < 10; i++ {
+		if id := next(); id.Time() != time.Unix(testIDSecs+1, 0) {
+			t.Fatalf("expected time %v, got %v", time.Unix(testIDSecs+1, 0), id.Time())
+		}
+	}
+
+	// once the clock catches up, the timestamp follows it again
+	now = time.Unix(testIDSecs+2, 0)
+	if id := next(); id.Time() != now || id.Counter() != 0 {
+		t.Fatalf("expected time %v and counter 0, got %v and %d", now, id.Time(), id.Counter())
+	}
+
+	t.Run("counter_overflow", func(t *testing.T) {
+		g.mu.Lock()
+		g.counter = 0xffffff
+		g.mu.Unlock()
+
+		if id := next(); id.Time() != now.Add(time.Second) || id.Counter() != 0 {
+			t.Fatalf("expected time %v and counter 0, got %v and %d", now.Add(time.Second), id.Time(), id.Counter())
+		}
+	})
+}
+
+func TestWithDatacenterByte(t *testing.T) {
+	g := NewGenerator(WithDatacenterByte(0xdc))
+
+	seen := map[ObjectID]bool{}
+	for i := 0; i < 1000; i++ {
+		id := g.New()
+		if id[4] != 0xdc {
+			t.Fatalf("expected datacenter byte dc, got %x", id[4])
+		}
+		if seen[id] {
+			t.Fatalf("expected unique ids, got %v twice", id)
+		}
+		seen[id] = true
+	}
+
+	other := NewGenerator(WithDatacenterByte(0xdc)).New()
+	if other[4] != 0xdc {
+		t.Fatalf("expected datacenter byte dc, got %x", other[4])
+	}
+}
+
+func TestGeneratorValidate(t *testing.T) {
+	setNow(t, time.Unix(testIDSecs, 0))
+	g := NewGenerator(WithDatacenterByte(0xdc))
+
+	t.Run("match", func(t *testing.T) {
+		if err := g.Validate(g.New()); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		// any generator with the same configuration is accepted
+		if err := g.Validate(NewGenerator(WithDatacenterByte(0xdc)).New()); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	})
+
+	t.Run("wrong_datacenter", func(t *testing.T) {
+		id := NewGenerator(WithDatacenterByte(0xab)).New()
+		expected := id.String() + " has datacenter byte ab, expected dc"
+		if err := g.Validate(id); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("before_generator", func(t *testing.T) {
+		id, err := g.New().WithTimestamp(time.Unix(testIDSecs-1, 0))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		expected := id.String() + " was created at 2019-09-04T08:04:00Z, before its generator at 2019-09-04T08:04:01Z"
+		if err := g.Validate(id); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if err := g.Validate(ObjectID("123")); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("no_datacenter", func(t *testing.T) {
+		if err := NewGenerator().Validate(g.New()); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	})
+}
+
+func TestCounterSeed(t *testing.T) {
+	start := time.Unix(testIDSecs, 0)
+
+	t.Run("stable", func(t *testing.T) {
+		if counterSeed("web-1", 42, start) != counterSeed("web-1", 42, start) {
+			t.Fatalf("expected equal seeds for equal inputs")
+		}
+	})
+
+	t.Run("hostnames_differ", func(t *testing.T) {
+		if counterSeed("web-1", 42, start) == counterSeed("web-2", 42, start) {
+			t.Fatalf("expected different seeds for different hostnames")
+		}
+	})
+
+	t.Run("24_bit", func(t *testing.T) {
+		if s := CounterSeedFromHost(); s > 0xffffff {
+			t.Fatalf("expected a 24-bit seed, got %d", s)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		g := NewGenerator(WithDerivedCounterStart())
+		if g.New().Counter() != int32((CounterSeedFromHost()+1)&0xffffff) {
+			t.Fatalf("expected counter to start after the derived seed")
+		}
+	})
+}
+
+func TestStressTestUniqueness(t *testing.T) {
+	if c := StressTestUniqueness(100000); c != 0 {
+		t.Fatalf("expected 0 collisions, got %d", c)
+	}
+}
+
+// idSink keeps the results of the generator benchmarks alive so their
+// allocations are not optimized away.
+var idSink ObjectID
+
+func BenchmarkNewObjectID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		idSink = NewObjectID()
+	}
+}
+
+func BenchmarkGenerator(b *testing.B) {
+	g := NewGenerator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		idSink = g.New()
+	}
+}
+
+func BenchmarkHybridGenerator(b *testing.B) {
+	g := NewHybridGenerator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		idSink = g.New()
+	}
+}
+
+func BenchmarkPrimitiveNewObjectID(b *testing.B) {
+	var p primitive.ObjectID
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p = primitive.NewObjectID()
+	}
+	idSink = ObjectID(p[:])
+}
@@ -22,16 +22,25 @@ package oid
 
 import (
 	"bytes"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/big"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/x/bsonx"
 )
 
 // ObjectID is a unique ID identifying a BSON value. It must be exactly 12 bytes
@@ -41,17 +50,35 @@ import (
 // http://www.mongodb.org/display/DOCS/Object+Ids
 type ObjectID string
 
+// NilObjectID is the empty ObjectID, the value of an unset id.
+const NilObjectID ObjectID = ""
+
 // ObjectIDHex returns an ObjectID from the provided hex representation.
 // Calling this function with an invalid hex representation will
-// return an error. See the IsObjectIDHex function.
+// return an error. See the IsObjectIDHex function. Valid hex of the wrong
+// length gets a distinct error naming the decoded length.
 func ObjectIDHex(s string) (ObjectID, error) {
 	d, err := hex.DecodeString(s)
-	if err != nil || len(d) != 12 {
+	if err != nil {
 		return ObjectID(d), fmt.Errorf("invalid input to ObjectIDHex: %q", s)
 	}
+	if len(d) != 12 {
+		return ObjectID(d), fmt.Errorf("invalid input to ObjectIDHex: %q: decoded length was %s (expected 12)", s, plural(len(d), "byte"))
+	}
 	return ObjectID(d), nil
 }
 
+// MustObjectIDHex is like ObjectIDHex but panics if s is not a valid hex
+// representation. It simplifies safe initialization of package-level ids and
+// test fixtures.
+func MustObjectIDHex(s string) ObjectID {
+	id, err := ObjectIDHex(s)
+	if err != nil {
+		panic(fmt.Sprintf("oid: MustObjectIDHex(%q): %v", s, err))
+	}
+	return id
+}
+
 // IsObjectIDHex returns whether s is a valid hex representation of
 // an ObjectID. See the ObjectIDHex function.
 func IsObjectIDHex(s string) bool {
@@ -59,10 +86,38 @@ func IsObjectIDHex(s string) bool {
 	return err == nil
 }
 
+// ClassifyString reports which ObjectID form s is in. It returns "raw12" when s
+// is exactly 12 bytes long and can be used directly as an ObjectID, "hex24" when
+// s is a 24 character hex representation that must go through ObjectIDHex, and
+// "" with ok set to false when s is neither.
+func ClassifyString(s string) (kind string, ok bool) {
+	switch {
+	case len(s) == 12:
+		return "raw12", true
+	case len(s) == 24 && IsObjectIDHex(s):
+		return "hex24", true
+	}
+	return "", false
+}
+
 // NewObjectID returns a new unique ObjectID.
 func NewObjectID() ObjectID {
-	id, _ := ObjectIDHex(primitive.NewObjectID().Hex())
-	return id
+	p := primitive.NewObjectID()
+	return ObjectID(p[:])
+}
+
+// NewObjectIDChecked returns a new unique ObjectID. The bytes are copied
+// directly from the driver, so the error is always nil; it is kept for
+// callers written against the earlier hex-based conversion.
+func NewObjectIDChecked() (ObjectID, error) {
+	return NewObjectID(), nil
+}
+
+// Intern returns a copy of id backed by freshly allocated memory. Use it when
+// storing ids that were built over a reused buffer with unsafe conversions, so
+// later writes to that buffer cannot change the stored id.
+func Intern(id ObjectID) ObjectID {
+	return ObjectID(strings.Clone(string(id)))
 }
 
 // String returns a hex string representation of the id.
@@ -71,9 +126,143 @@ func (id ObjectID) String() string {
 	return fmt.Sprintf(`ObjectID("%x")`, string(id))
 }
 
+// Set parses s with ParseObjectID and stores the result in id, so that together
+// with String an *ObjectID implements flag.Value:
+//
+//	var id oid.ObjectID
+//	flag.Var(&id, "id", "target object id")
+//
+// Hex is the expected input, but the String form is accepted as well. id is
+// left unchanged on error.
+func (id *ObjectID) Set(s string) error {
+	out, err := ParseObjectID(s)
+	if err != nil {
+		return err
+	}
+	*id = out
+	return nil
+}
+
 // Hex returns a hex representation of the ObjectID.
 func (id ObjectID) Hex() string {
-	return hex.EncodeToString([]byte(id))
+	var buf [24]byte
+	return string(id.AppendHex(buf[:0]))
+}
+
+const hextable = "0123456789abcdef"
+
+// AppendHex appends the lowercase hex representation of the ObjectID to dst and
+// returns the extended buffer, as Hex does but without allocating when dst has
+// room for it.
+func (id ObjectID) AppendHex(dst []byte) []byte {
+	for i := 0; i < len(id); i++ {
+		dst = append(dst, hextable[id[i]>>4], hextable[id[i]&0x0f])
+	}
+	return dst
+}
+
+// HexArray returns the hex representation of the ObjectID as a fixed size
+// array, avoiding the string allocation of Hex for fixed-width records. It
+// returns an error for an invalid id.
+func (id ObjectID) HexArray() ([24]byte, error) {
+	var dst [24]byte
+	if len(id) != 12 {
+		return dst, fmt.Errorf("invalid ObjectID: %q", string(id))
+	}
+	for i := 0; i < 12; i++ {
+		dst[i*2] = hextable[id[i]>>4]
+		dst[i*2+1] = hextable[id[i]&0x0f]
+	}
+	return dst, nil
+}
+
+// ExternalKey returns the canonical lowercase hex of the id for use as a key in
+// external systems. Unlike Hex, it returns an error when the id is not a valid
+// 12-byte ObjectID.
+func (id ObjectID) ExternalKey() (string, error) {
+	if !id.Valid() {
+		return "", fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return id.Hex(), nil
+}
+
+// CacheKey returns the cache key prefix:<hex> for the id, such as
+// "user:5d6f6ff1646327ce31968d93". It returns an error for an invalid id so
+// nothing is ever cached under a malformed key.
+func (id ObjectID) CacheKey(prefix string) (string, error) {
+	key, err := id.ExternalKey()
+	if err != nil {
+		return "", err
+	}
+	return prefix + ":" + key, nil
+}
+
+// IndexKey returns the raw 12 bytes of the id for use as a key in stores that
+// order keys with memcmp. The timestamp is stored big-endian in the leading
+// bytes and the counter big-endian in the trailing bytes, so the byte order of
+// keys is chronological order, with ids from the same second and generator
+// ordered by counter. It returns an error for an invalid id.
+func (id ObjectID) IndexKey() ([]byte, error) {
+	if !id.Valid() {
+		return nil, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return []byte(id), nil
+}
+
+// PrefixUpperBound returns the exclusive upper bound for a range scan over the
+// index keys starting with prefix: the smallest 12-byte value greater than every
+// id with that prefix. It is found by incrementing the last byte of prefix that
+// is not 0xff and zeroing the bytes after it. Only the first 12 bytes of prefix
+// are used. An empty prefix, or one made only of 0xff bytes, has no upper bound
+// and returns the empty ObjectID.
+func PrefixUpperBound(prefix []byte) ObjectID {
+	if len(prefix) > 12 {
+		prefix = prefix[:12]
+	}
+	var b [12]byte
+	copy(b[:], prefix)
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if b[i] != 0xff {
+			b[i]++
+			return ObjectID(b[:])
+		}
+		b[i] = 0
+	}
+	return ""
+}
+
+// EqualHexString reports whether s is the hex representation of id, in either
+// case. It decodes s into a stack buffer rather than allocating with Hex, which
+// suits hot comparisons such as authorization checks. It returns false when s
+// is not 24 hex characters or id is invalid.
+func (id ObjectID) EqualHexString(s string) bool {
+	if len(id) != 12 || len(s) != 24 {
+		return false
+	}
+
+	var buf [12]byte
+	for i := 0; i < 12; i++ {
+		hi, ok1 := fromHexChar(s[i*2])
+		lo, ok2 := fromHexChar(s[i*2+1])
+		if !ok1 || !ok2 {
+			return false
+		}
+		buf[i] = hi<<4 | lo
+	}
+	return string(buf[:]) == string(id)
+}
+
+// fromHexChar converts a hex character into its value and a success flag.
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
 }
 
 // Valid confirms that the objectID is valid
@@ -82,6 +271,130 @@ func (id ObjectID) Valid() bool {
 	return err == nil
 }
 
+// IsZero reports whether the id is unset: either empty, which is NilObjectID,
+// or twelve zero bytes, the zero value of primitive.ObjectID. Both are valid
+// states for a field that has not been assigned yet. This differs from Valid,
+// which reports whether the id has the 12 bytes of an ObjectID; an empty id is
+// zero but not valid, a zero-byte id is both, and a malformed id of any other
+// length is neither. IsZero never panics.
+//
+// The BSON encoder consults IsZero for omitempty fields, so a zero id, or a
+// non-nil pointer to one, is omitted just like a zero primitive.ObjectID. Drop
+// omitempty to write an unset id as null instead.
+func (id ObjectID) IsZero() bool {
+	return id == NilObjectID || id == "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"
+}
+
+// Equal reports whether id and other hold the same bytes. Two empty ids are
+// equal.
+func (id ObjectID) Equal(other ObjectID) bool {
+	return id == other
+}
+
+// Compare returns -1, 0 or 1 as id sorts before, equal to or after other,
+// comparing the raw bytes in the same order MongoDB uses. Ids of any length,
+// including empty and malformed ones, are compared without panicking, with a
+// shorter id sorting before any longer id it is a prefix of.
+func (id ObjectID) Compare(other ObjectID) int {
+	return strings.Compare(string(id), string(other))
+}
+
+// SecureEqual reports whether id and other hold the same 12 bytes, comparing
+// them in constant time. It is meant only for security-sensitive comparisons,
+// such as checking an id used as an unguessable token; use Equal everywhere
+// else. Unlike Equal, it returns false unless both ids are valid, so an unset
+// token never matches another unset token.
+func (id ObjectID) SecureEqual(other ObjectID) bool {
+	if len(id) != 12 || len(other) != 12 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(id), []byte(other)) == 1
+}
+
+// LogString returns a representation of the id that is safe to write to logs.
+// A valid id is rendered as its bare hex, an empty id as "<empty>" and a
+// malformed id as "<invalid:...>" holding the hex of whatever bytes it has, so
+// the output never contains raw bytes and is never mistaken for a real id.
+func (id ObjectID) LogString() string {
+	switch {
+	case id == "":
+		return "<empty>"
+	case !id.Valid():
+		return "<invalid:" + id.Hex() + ">"
+	}
+	return id.Hex()
+}
+
+// MetricLabel returns the id as a metrics label value: the bare hex for a valid
+// id and "invalid" otherwise, so the label is always short, printable and
+// non-empty.
+func (id ObjectID) MetricLabel() string {
+	if !id.Valid() {
+		return "invalid"
+	}
+	return id.Hex()
+}
+
+// Pseudonymize returns a stable, non-reversible stand-in for the id: the first
+// 24 hex characters of HMAC-SHA256(salt, id). The same id always maps to the
+// same pseudonym under a given salt, so analytics events can still be joined
+// without exposing the real id.
+func (id ObjectID) Pseudonymize(salt []byte) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:24]
+}
+
+// Obfuscate returns a reversible, URL-safe encoding of the id that hides its
+// time-ordered layout from clients. The id is padded with 4 zero bytes to a
+// single 16-byte block, encrypted with AES-128 under key and encoded as
+// unpadded base64url. An invalid id returns an empty string. See
+// DeobfuscateObjectID.
+func (id ObjectID) Obfuscate(key [16]byte) string {
+	if !id.Valid() {
+		return ""
+	}
+	block, _ := aes.NewCipher(key[:]) // a 16-byte key cannot fail
+	var b [aes.BlockSize]byte
+	copy(b[:], id)
+	block.Encrypt(b[:], b[:])
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// DeobfuscateObjectID reverses Obfuscate. A string that is not the output of
+// Obfuscate under key, including one produced with a different key, returns an
+// error, as the decrypted padding bytes are then not zero.
+func DeobfuscateObjectID(s string, key [16]byte) (ObjectID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) != aes.BlockSize {
+		return "", fmt.Errorf("invalid obfuscated ObjectID: %q", s)
+	}
+	block, _ := aes.NewCipher(key[:])
+	block.Decrypt(b, b)
+	if !bytes.Equal(b[12:], []byte{0, 0, 0, 0}) {
+		return "", fmt.Errorf("invalid obfuscated ObjectID: %q", s)
+	}
+	return ObjectID(b[:12]), nil
+}
+
+// HashInto fills hashes with len(hashes) 64-bit hashes of the raw id bytes for
+// bloom filter indexing. The hashes are derived by double hashing, h1 + i*h2,
+// from the FNV-1a and FNV-1 hashes of the id, and are deterministic across
+// processes and releases.
+func (id ObjectID) HashInto(hashes []uint64) {
+	f1 := fnv.New64a()
+	f1.Write([]byte(id))
+	h1 := f1.Sum64()
+
+	f2 := fnv.New64()
+	f2.Write([]byte(id))
+	h2 := f2.Sum64() | 1 // odd, so the hashes never collapse to h1
+
+	for i := range hashes {
+		hashes[i] = h1 + uint64(i)*h2
+	}
+}
+
 // byteSlice returns byte slice of id from start to end.
 // Calling this function with an invalid id will cause a runtime panic.
 func (id ObjectID) byteSlice(start, end int) []byte {
@@ -91,14 +404,39 @@ func (id ObjectID) byteSlice(start, end int) []byte {
 	return []byte(string(id)[start:end])
 }
 
+// check returns the error byteSlice would panic with for an invalid id.
+func (id ObjectID) check() error {
+	if len(id) != 12 {
+		return fmt.Errorf("invalid ObjectID: %q", string(id))
+	}
+	return nil
+}
+
 // Time returns the timestamp part of the id.
-// It's a runtime error to call this method with an invalid id.
+// It's a runtime error to call this method with an invalid id; use TimeChecked
+// for ids from untrusted input.
 func (id ObjectID) Time() time.Time {
 	// First 4 bytes of ObjectID is 32-bit big-endian seconds from epoch.
 	secs := int64(binary.BigEndian.Uint32(id.byteSlice(0, 4)))
 	return time.Unix(secs, 0)
 }
 
+// TimeChecked is like Time but returns an error for an invalid id instead of
+// panicking.
+func (id ObjectID) TimeChecked() (time.Time, error) {
+	if err := id.check(); err != nil {
+		return time.Time{}, err
+	}
+	return id.Time(), nil
+}
+
+// HasZeroTimestamp reports whether id is a 12-byte id whose timestamp is all
+// zeros, i.e. the 1970 epoch. Such ids are usually time boundaries or synthetic
+// values that leaked into real data. It returns false for an invalid id.
+func (id ObjectID) HasZeroTimestamp() bool {
+	return len(id) == 12 && id[:4] == "\x00\x00\x00\x00"
+}
+
 // Note: The ObjectID spec was changed in 2018: Machine ID and
 // ProcessID were replaced by a single 5-byte random value. According
 // to the spec, drivers MUST NOT have an accessor method on an ObjectID
@@ -106,123 +444,434 @@ func (id ObjectID) Time() time.Time {
 // https://github.com/mongodb/specifications/blob/master/source/objectid.rst
 
 // Deprecated: Machine returns the 3-byte machine id part of the id.
-// It's a runtime error to call this method with an invalid id.
+// It's a runtime error to call this method with an invalid id; see
+// MachineChecked.
 func (id ObjectID) Machine() []byte {
 	return id.byteSlice(4, 7)
 }
 
+// Deprecated: MachineChecked is like Machine but returns an error for an
+// invalid id instead of panicking.
+func (id ObjectID) MachineChecked() ([]byte, error) {
+	if err := id.check(); err != nil {
+		return nil, err
+	}
+	return id.Machine(), nil
+}
+
 // Deprecated: Pid returns the process id part of the id.
-// It's a runtime error to call this method with an invalid id.
+// It's a runtime error to call this method with an invalid id; see PidChecked.
 func (id ObjectID) Pid() uint16 {
 	return binary.BigEndian.Uint16(id.byteSlice(7, 9))
 }
 
+// Deprecated: PidChecked is like Pid but returns an error for an invalid id
+// instead of panicking.
+func (id ObjectID) PidChecked() (uint16, error) {
+	if err := id.check(); err != nil {
+		return 0, err
+	}
+	return id.Pid(), nil
+}
+
 // Counter returns the incrementing value part of the id.
-// It's a runtime error to call this method with an invalid id.
+// It's a runtime error to call this method with an invalid id; use
+// CounterChecked for ids from untrusted input.
 func (id ObjectID) Counter() int32 {
 	b := id.byteSlice(9, 12)
 	// Counter is stored as big-endian 3-byte value
 	return int32(uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]))
 }
 
-// MarshalBSONValue satisfies the decoding interface for the mongo driver
+// CounterChecked is like Counter but returns an error for an invalid id
+// instead of panicking.
+func (id ObjectID) CounterChecked() (int32, error) {
+	if err := id.check(); err != nil {
+		return 0, err
+	}
+	return id.Counter(), nil
+}
+
+// CounterBytes returns a copy of the 3-byte big-endian counter part of the id.
+// Unlike Counter, it returns an error for an invalid id instead of panicking.
+func (id ObjectID) CounterBytes() ([]byte, error) {
+	if len(id) != 12 {
+		return nil, fmt.Errorf("invalid ObjectID: %q", string(id))
+	}
+	return []byte(string(id)[9:12]), nil
+}
+
+// NextWithOverflow returns the id with its 24-bit counter incremented by one,
+// and whether the counter overflowed. On overflow the counter wraps to zero and
+// the timestamp is left untouched, so the successor sorts before id; callers
+// that rely on time ordering must bump the timestamp themselves. An invalid id
+// returns an empty id and false.
+func (id ObjectID) NextWithOverflow() (ObjectID, bool) {
+	if len(id) != 12 {
+		return "", false
+	}
+
+	b := []byte(id)
+	for i := 11; i >= 9; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return ObjectID(b), false
+		}
+	}
+	return ObjectID(b), true
+}
+
+// CounterDistance returns the counter of id minus the counter of other. The
+// difference is only meaningful when both ids were created in the same second,
+// so ok is false when their timestamps differ or either id is invalid.
+func (id ObjectID) CounterDistance(other ObjectID) (int32, bool) {
+	if !id.Valid() || !other.Valid() || id[:4] != other[:4] {
+		return 0, false
+	}
+	return id.Counter() - other.Counter(), true
+}
+
+// CoGenerated reports whether id and other share both their timestamp and their
+// 5-byte random value, suggesting they were generated by the same process in
+// the same second. It is a heuristic for grouping events by instance. It
+// returns false if either id is invalid.
+func (id ObjectID) CoGenerated(other ObjectID) bool {
+	if !id.Valid() || !other.Valid() {
+		return false
+	}
+	return id[:9] == other[:9]
+}
+
+// EqualIgnoringTime reports whether id and other share their random value and
+// counter, bytes 4 to 11, whatever their timestamps. It is a heuristic for
+// spotting a generation that was retried with a new timestamp. It returns false
+// if either id is invalid.
+func (id ObjectID) EqualIgnoringTime(other ObjectID) bool {
+	if !id.Valid() || !other.Valid() {
+		return false
+	}
+	return id[4:] == other[4:]
+}
+
+// Between reports whether lo <= id <= hi comparing the raw bytes, which is the
+// order MongoDB uses for ObjectIDs. It returns false if any of the ids is
+// invalid.
+func (id ObjectID) Between(lo, hi ObjectID) bool {
+	if !id.Valid() || !lo.Valid() || !hi.Valid() {
+		return false
+	}
+	return lo <= id && id <= hi
+}
+
+// PositionIn returns where id lies between min and max as a fraction from 0 to
+// 1, treating the ids as 96-bit big-endian integers, such as for mapping an id
+// to a slider over a known range of ids. Ids outside the range are clamped to
+// it. It returns 0 if any of the ids is invalid or max is not after min.
+func (id ObjectID) PositionIn(min, max ObjectID) float64 {
+	if !id.Valid() || !min.Valid() || !max.Valid() || max <= min {
+		return 0
+	}
+	switch {
+	case id <= min:
+		return 0
+	case id >= max:
+		return 1
+	}
+
+	lo := new(big.Int).SetBytes([]byte(min))
+	num := new(big.Int).Sub(new(big.Int).SetBytes([]byte(id)), lo)
+	den := new(big.Int).Sub(new(big.Int).SetBytes([]byte(max)), lo)
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(num), new(big.Float).SetInt(den)).Float64()
+	return f
+}
+
+// MarshalBSONValue satisfies the decoding interface for the mongo driver.
+// An empty id is marshalled as BSON null, so a field without omitempty, or a
+// non-nil pointer to an empty id, is stored as an explicit null. Under
+// omitempty the driver checks IsZero first and omits the field instead.
 func (id ObjectID) MarshalBSONValue() (bsontype.Type, []byte, error) {
-	objID, err := primitive.ObjectIDFromHex(id.Hex())
+	if id == "" {
+		return bsontype.Null, nil, nil
+	}
+
+	objID, err := id.ToPrimitive()
 	if err != nil {
-		return bsontype.ObjectID, []byte{}, fmt.Errorf("%s is not an ObjectID", id.String())
+		return bsontype.ObjectID, []byte{}, err
 	}
 
-	val := bsonx.ObjectID(objID)
-	return val.MarshalBSONValue()
+	return bsontype.ObjectID, objID[:], nil
 }
 
+// UnwrapSingleElementArrays makes UnmarshalBSONValue accept a BSON array holding
+// exactly one ObjectID or string and decode its element. This rescues legacy
+// documents that stored a single id as a one-element array. Arrays with any
+// other number of elements are still rejected. It is off by default and should
+// be set once at init.
+var UnwrapSingleElementArrays = false
+
+// BSONValidator, when set, is called by UnmarshalBSONValue with every id it
+// decodes from a BSON ObjectID or string, so that domain rules such as
+// rejecting the zero id can be enforced on all data read from MongoDB. An error
+// from it fails the decode and leaves the id unchanged. Null values are not
+// passed to it. It is nil by default and should be set once at init.
+var BSONValidator func(ObjectID) error
+
+// StringCoercions counts how many times UnmarshalBSONValue has decoded an
+// ObjectID from a BSON string rather than a BSON ObjectID. Reading it after a
+// batch decode shows how many documents still store ids as strings. See
+// ResetStats.
+var StringCoercions atomic.Int64
+
+// ResetStats resets the decode statistics such as StringCoercions to zero.
+func ResetStats() {
+	StringCoercions.Store(0)
+}
+
+// errTooFewBytes matches the driver's error for a value cut short.
+var errTooFewBytes = errors.New("too few bytes to read next component")
+
 // UnmarshalBSONValue satisfies the decoding interface for the mongo driver
 func (id *ObjectID) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	raw := bson.RawValue{Type: t, Value: b}
+
+	if t == bsontype.Array && UnwrapSingleElementArrays {
+		arr, ok := raw.ArrayOK()
+		if !ok {
+			return fmt.Errorf("invalid objectID from source: %v", errTooFewBytes)
+		}
+		vals, err := arr.Values()
+		if err != nil {
+			return fmt.Errorf("invalid objectID from source: %v", err)
+		}
+		if len(vals) != 1 {
+			return fmt.Errorf("array of %d elements cannot be converted to %s", len(vals), bsontype.ObjectID)
+		}
+		return id.UnmarshalBSONValue(vals[0].Type, vals[0].Value)
+	}
+
+	if t == bsontype.Null || t == bsontype.Undefined {
+		*id = ""
+		return nil
+	}
+
 	if t != bsontype.ObjectID && t != bsontype.String {
 		return fmt.Errorf("type %s cannot be converted to %s", t, bsontype.ObjectID)
 	}
 
-	val := bsonx.Undefined()
-	if err := val.UnmarshalBSONValue(t, b); err != nil {
-		return fmt.Errorf("invalid objectID from source: %v", err)
+	if t == bsontype.ObjectID {
+		p, ok := raw.ObjectIDOK()
+		if !ok {
+			return fmt.Errorf("invalid objectID from source: %v", errTooFewBytes)
+		}
+		return id.setValidated(FromPrimitive(p))
 	}
 
-	var oid ObjectID
-	var err error
-	if t == bsontype.ObjectID {
-		oid, err = ObjectIDHex(val.ObjectID().Hex())
-	} else {
-		oid, err = ObjectIDHex(val.String())
+	str, ok := raw.StringValueOK()
+	if !ok {
+		return fmt.Errorf("invalid objectID from source: %v", errTooFewBytes)
 	}
 
+	oid, err := ObjectIDHex(str)
 	if nil != err {
 		return fmt.Errorf("error occurred while trying to convert, reason: %s", err)
 	}
 
-	*id = oid
+	if err := id.setValidated(oid); err != nil {
+		return err
+	}
+	StringCoercions.Add(1)
+	return nil
+}
 
+// setValidated stores oid in id once it has passed BSONValidator.
+func (id *ObjectID) setValidated(oid ObjectID) error {
+	if BSONValidator != nil {
+		if err := BSONValidator(oid); err != nil {
+			return fmt.Errorf("%s failed validation: %w", oid.String(), err)
+		}
+	}
+	*id = oid
 	return nil
 }
 
+// JSONMode selects how MarshalJSON renders ObjectIDs. See JSONFormat.
+type JSONMode int
+
+const (
+	// JSONHex renders ids as a bare hex string and an empty id as "".
+	JSONHex JSONMode = iota
+	// JSONExtended renders ids in the extended JSON form {"$oid": "<hex>"} and
+	// an empty id as null.
+	JSONExtended
+	// JSONNull renders ids as a bare hex string and an empty id as null.
+	JSONNull
+)
+
+// JSONFormat controls the output of MarshalJSON for every ObjectID in the
+// program. UnmarshalJSON accepts all of the forms regardless of its value. It
+// is read without synchronisation, so it must be set once during
+// initialisation, before any marshalling happens.
+var JSONFormat = JSONHex
+
 // MarshalJSON turns a bson.ObjectID into a json.Marshaller.
 func (id ObjectID) MarshalJSON() ([]byte, error) {
+	if JSONFormat == JSONExtended {
+		return id.MarshalExtendedJSON()
+	}
+	if id == "" && JSONFormat == JSONNull {
+		return nullBytes, nil
+	}
 	return []byte("\"" + id.Hex() + "\""), nil
 }
 
+// MarshalExtendedJSON renders the id in the canonical extended JSON form
+// {"$oid":"<hex>"} used by mongoexport and mongoimport, and an empty id as
+// null, whatever the value of JSONFormat. Use it to produce extended JSON for
+// a single exchange without changing the output of MarshalJSON elsewhere in
+// the program.
+func (id ObjectID) MarshalExtendedJSON() ([]byte, error) {
+	if id == "" {
+		return nullBytes, nil
+	}
+	return []byte(`{"$oid":"` + id.Hex() + `"}`), nil
+}
+
+// WithTimeJSON returns the id together with its creation time as the JSON
+// object {"id": "<hex>", "createdAt": "<RFC3339>"}, with the time in UTC. It
+// returns an error for an invalid id.
+func (id ObjectID) WithTimeJSON() ([]byte, error) {
+	if !id.Valid() {
+		return nil, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return json.Marshal(struct {
+		ID        string `json:"id"`
+		CreatedAt string `json:"createdAt"`
+	}{
+		ID:        id.Hex(),
+		CreatedAt: id.Time().UTC().Format(time.RFC3339),
+	})
+}
+
 var nullBytes = []byte("null")
 
-// UnmarshalJSON populates the byte slice with the ObjectID. If the byte slice is 64 bytes long, it
-// will be populated with the hex representation of the ObjectID. If the byte slice is twelve bytes
-// long, it will be populated with the BSON representation of the ObjectID. Otherwise, it will
-// return an error.
+// UnmarshalJSON populates the ObjectID from its JSON representation: a string holding the 24
+// character hex of the id. JSON text is never treated as raw ObjectID bytes, so a string of any
+// other length returns an error.
+//
+// Objects are accepted in the extended JSON form {"$oid": "<hex>"} and, for compatibility with
+// older API shapes, the {"id": "<hex>"} form. The $oid key takes precedence when both are present.
+//
+// An empty string or null, whether bare or as the value of $oid or id, leaves the id empty.
 func (id *ObjectID) UnmarshalJSON(b []byte) error {
-	var buf [12]byte
-	switch len(b) {
-	case 12:
-		_, err := hex.Decode(buf[:], b)
-		if err != nil {
-			return fmt.Errorf("invalid ObjectID in JSON: %s (%s)", string(b), err)
-		}
-	default:
+	if len(b) == 2 && b[0] == '"' && b[1] == '"' || bytes.Equal(b, nullBytes) {
+		*id = ""
+		return nil
+	}
 
-		// Extended JSON
-		var res interface{}
-		if err := json.Unmarshal(b, &res); err != nil {
-			return err
+	// Extended JSON
+	var res interface{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+	str, ok := res.(string)
+	if !ok {
+		m, ok := res.(map[string]interface{})
+		if !ok {
+			return errors.New("not an extended JSON ObjectID")
 		}
-		str, ok := res.(string)
+		oid, ok := m["$oid"]
 		if !ok {
-			m, ok := res.(map[string]interface{})
-			if !ok {
-				return errors.New("not an extended JSON ObjectID")
-			}
-			oid, ok := m["$oid"]
-			if !ok {
-				return errors.New("not an extended JSON ObjectID")
-			}
-			str, ok = oid.(string)
-			if !ok {
-				return errors.New("not an extended JSON ObjectID")
-			}
-
+			oid, ok = m["id"]
 		}
-
-		if len(b) == 2 && b[0] == '"' && b[1] == '"' || bytes.Equal(b, nullBytes) {
+		if !ok {
+			return errors.New("not an extended JSON ObjectID")
+		}
+		if oid == nil {
 			*id = ""
 			return nil
 		}
-
-		if len(str) != 24 {
-			return fmt.Errorf("invalid ObjectID in JSON: %s", str)
+		str, ok = oid.(string)
+		if !ok {
+			return errors.New("not an extended JSON ObjectID")
 		}
-
-		_, err := hex.Decode(buf[:], []byte(str))
-		if err != nil {
-			return fmt.Errorf("invalid ObjectID in JSON: %s (%s)", string(b), err)
+		if str == "" {
+			*id = ""
+			return nil
 		}
+	}
+
+	if len(str) != 24 {
+		return fmt.Errorf("invalid ObjectID in JSON: %s", str)
+	}
+
+	var buf [12]byte
+	_, err := hex.Decode(buf[:], []byte(str))
+	if err != nil {
+		return fmt.Errorf("invalid ObjectID in JSON: %s (%s)", string(b), err)
+	}
+
+	*id = ObjectID(string(buf[:]))
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the 24 character hex
+// of the id. An empty id marshals to empty text and any other invalid id
+// returns an error.
+//
+// Note that encoding/json writes map keys of a string type such as ObjectID
+// verbatim without calling MarshalText, so convert keys with Hex before
+// encoding a map keyed by ObjectID. Decoding such a map uses UnmarshalText.
+func (id ObjectID) MarshalText() ([]byte, error) {
+	if id == "" {
+		return []byte{}, nil
+	}
+	if !id.Valid() {
+		return nil, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return []byte(id.Hex()), nil
+}
 
-		*id = ObjectID(string(buf[:]))
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the 24
+// character hex of an id, the ObjectID("<hex>") form produced by String, and
+// empty text, which leaves the id empty. The id is left unchanged on error.
+func (id *ObjectID) UnmarshalText(b []byte) error {
+	s := string(b)
+	if s == "" {
+		*id = ""
+		return nil
 	}
+	if strings.HasPrefix(s, `ObjectID("`) && strings.HasSuffix(s, `")`) {
+		s = s[len(`ObjectID("`) : len(s)-len(`")`)]
+	}
+	v, err := ObjectIDHex(s)
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
 
+// MarshalBinary implements encoding.BinaryMarshaler, returning a copy of the 12
+// raw bytes of the id. This is also the form used by encoding/gob. An empty id
+// marshals to no bytes and any other invalid id returns an error.
+func (id ObjectID) MarshalBinary() ([]byte, error) {
+	if id == "" {
+		return []byte{}, nil
+	}
+	if !id.Valid() {
+		return nil, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return []byte(id), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with the same rules as
+// ObjectIDFromBytes: 12 bytes are copied into the id, no bytes leave the id
+// empty, and any other length returns an error and leaves the id unchanged.
+func (id *ObjectID) UnmarshalBinary(b []byte) error {
+	v, err := ObjectIDFromBytes(b)
+	if err != nil {
+		return err
+	}
+	*id = v
 	return nil
 }
@@ -1,17 +1,25 @@
 package oid
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+	"unsafe"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -151,6 +159,66 @@ func TestObjectID(t *testing.T) {
 		})
 	})
 
+	t.Run("string_coercions", func(t *testing.T) {
+		tearUp(t, func(ctx context.Context, e *mongo.Collection) {
+			objID, _ := primitive.ObjectIDFromHex(testID)
+			docs := []interface{}{
+				bson.M{"_id": primitive.NewObjectID(), "s": testID},
+				bson.M{"_id": primitive.NewObjectID(), "s": objID},
+				bson.M{"_id": primitive.NewObjectID(), "s": testID},
+			}
+			if _, err := e.InsertMany(ctx, docs); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+
+			type resp struct {
+				S ObjectID `bson:"s"`
+			}
+
+			ResetStats()
+			cur, err := e.Find(ctx, bson.M{})
+			if err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+			var out []resp
+			if err := cur.All(ctx, &out); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+
+			if len(out) != 3 {
+				t.Fatalf("expected 3 documents, got %d", len(out))
+			}
+			if n := StringCoercions.Load(); n != 2 {
+				t.Fatalf("expected 2 string coercions, got %d", n)
+			}
+
+			ResetStats()
+			if n := StringCoercions.Load(); n != 0 {
+				t.Fatalf("expected 0 after reset, got %d", n)
+			}
+		})
+	})
+
+	t.Run("id_null", func(t *testing.T) {
+		tearUp(t, func(ctx context.Context, e *mongo.Collection) {
+			if _, err := e.InsertOne(ctx, bson.M{"_id": primitive.NewObjectID(), "v": nil}); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+
+			var out test
+			res := e.FindOne(ctx, bson.M{"v": nil})
+			if err := res.Err(); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+			if err := res.Decode(&out); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out.V != "" {
+				t.Fatalf("expected empty id, got %v", out.V)
+			}
+		})
+	})
+
 	t.Run("id_num", func(t *testing.T) {
 		tearUp(t, func(ctx context.Context, e *mongo.Collection) {
 			b := test{
@@ -183,6 +251,244 @@ func TestObjectID(t *testing.T) {
 	})
 }
 
+func TestMarshalBSONPointer(t *testing.T) {
+	type ptr struct {
+		P *ObjectID `bson:"p,omitempty"`
+	}
+
+	t.Run("nil_pointer", func(t *testing.T) {
+		b, err := bson.Marshal(ptr{})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if _, err := bson.Raw(b).LookupErr("p"); err == nil {
+			t.Fatalf("expected p to be omitted, got %s", bson.Raw(b))
+		}
+	})
+
+	t.Run("pointer_to_empty", func(t *testing.T) {
+		empty := ObjectID("")
+		b, err := bson.Marshal(ptr{P: &empty})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		// omitempty honours IsZero, so an empty id is omitted like a nil pointer
+		if _, err := bson.Raw(b).LookupErr("p"); err == nil {
+			t.Fatalf("expected p to be omitted, got %s", bson.Raw(b))
+		}
+
+		b, err = bson.Marshal(struct {
+			P *ObjectID `bson:"p"`
+		}{P: &empty})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		val, err := bson.Raw(b).LookupErr("p")
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if val.Type != bsontype.Null {
+			t.Fatalf("expected null, got %s", val.Type)
+		}
+	})
+
+	t.Run("pointer_to_valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+		b, err := bson.Marshal(ptr{P: &id})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		val, err := bson.Raw(b).LookupErr("p")
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if val.Type != bsontype.ObjectID || val.ObjectID().Hex() != testID {
+			t.Fatalf("expected ObjectID(%q), got %s", testID, val)
+		}
+	})
+}
+
+func TestUnmarshalBSONValueTruncated(t *testing.T) {
+	expected := "invalid objectID from source: too few bytes to read next component"
+	for _, typ := range []bsontype.Type{bsontype.ObjectID, bsontype.String} {
+		id := NewObjectID()
+		before := id
+		if err := id.UnmarshalBSONValue(typ, []byte{1, 2, 3}); err == nil || err.Error() != expected {
+			t.Fatalf("%s: expected %s, got %v", typ, expected, err)
+		}
+		if id != before {
+			t.Fatalf("%s: expected %v to be unchanged, got %v", typ, before, id)
+		}
+	}
+}
+
+func TestUnmarshalBSONNull(t *testing.T) {
+	type resp struct {
+		V ObjectID `bson:"v"`
+	}
+
+	for _, v := range []interface{}{nil, primitive.Undefined{}} {
+		b, err := bson.Marshal(bson.M{"v": v})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		out := resp{V: NewObjectID()}
+		if err := bson.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V != "" {
+			t.Fatalf("expected empty id, got %v", out.V)
+		}
+	}
+}
+
+func TestUnwrapSingleElementArrays(t *testing.T) {
+	type resp struct {
+		V ObjectID `bson:"v"`
+	}
+
+	expected, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	pID, _ := primitive.ObjectIDFromHex(testID)
+
+	single, err := bson.Marshal(bson.M{"v": bson.A{pID}})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	double, err := bson.Marshal(bson.M{"v": bson.A{pID, testID}})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var out resp
+		if err := bson.Unmarshal(single, &out); err == nil {
+			t.Fatalf("expected error, got %v", out)
+		}
+	})
+
+	UnwrapSingleElementArrays = true
+	defer func() { UnwrapSingleElementArrays = false }()
+
+	t.Run("one_element", func(t *testing.T) {
+		var out resp
+		if err := bson.Unmarshal(single, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V != expected {
+			t.Fatalf("expected %v, got %v", expected, out.V)
+		}
+	})
+
+	t.Run("one_string_element", func(t *testing.T) {
+		b, err := bson.Marshal(bson.M{"v": bson.A{testID}})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		var out resp
+		if err := bson.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V != expected {
+			t.Fatalf("expected %v, got %v", expected, out.V)
+		}
+	})
+
+	t.Run("two_elements", func(t *testing.T) {
+		var out resp
+		err := bson.Unmarshal(double, &out).(*bsoncodec.DecodeError)
+		if err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+		expected := "array of 2 elements cannot be converted to objectID"
+		if err.Unwrap().Error() != expected {
+			t.Fatalf("expected %s, got %s", expected, err.Unwrap().Error())
+		}
+	})
+}
+
+func TestBSONValidator(t *testing.T) {
+	type resp struct {
+		V ObjectID `bson:"v"`
+	}
+
+	errZero := errors.New("zero id")
+	BSONValidator = func(id ObjectID) error {
+		if id.IsZero() {
+			return errZero
+		}
+		return nil
+	}
+	defer func() { BSONValidator = nil }()
+
+	t.Run("rejected", func(t *testing.T) {
+		b, err := bson.Marshal(bson.M{"v": primitive.NilObjectID})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		out := resp{V: "unchanged"}
+		err = bson.Unmarshal(b, &out)
+		if !errors.Is(err, errZero) {
+			t.Fatalf("expected %v, got %v", errZero, err)
+		}
+		if out.V != "unchanged" {
+			t.Fatalf("expected the id to be unchanged, got %v", out.V)
+		}
+	})
+
+	t.Run("rejected_string", func(t *testing.T) {
+		b, err := bson.Marshal(bson.M{"v": "000000000000000000000000"})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		ResetStats()
+		var out resp
+		if err := bson.Unmarshal(b, &out); !errors.Is(err, errZero) {
+			t.Fatalf("expected %v, got %v", errZero, err)
+		}
+		if n := StringCoercions.Load(); n != 0 {
+			t.Fatalf("expected 0 string coercions, got %d", n)
+		}
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		pID, _ := primitive.ObjectIDFromHex(testID)
+		b, err := bson.Marshal(bson.M{"v": pID})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		var out resp
+		if err := bson.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V.Hex() != testID {
+			t.Fatalf("expected %s, got %v", testID, out.V)
+		}
+	})
+
+	t.Run("null_not_validated", func(t *testing.T) {
+		b, err := bson.Marshal(bson.M{"v": nil})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		var out resp
+		if err := bson.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	})
+}
+
 func TestObjectIDHex(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		id, err := ObjectIDHex(testID)
@@ -195,9 +501,25 @@ func TestObjectIDHex(t *testing.T) {
 		}
 	})
 
+	t.Run("non_hex", func(t *testing.T) {
+		_, err := ObjectIDHex("5d6f6ff1646327ce31968d9x")
+		expected := `invalid input to ObjectIDHex: "5d6f6ff1646327ce31968d9x"`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("wrong_length", func(t *testing.T) {
+		_, err := ObjectIDHex("5d6f6ff1646327ce3196")
+		expected := `invalid input to ObjectIDHex: "5d6f6ff1646327ce3196": decoded length was 10 bytes (expected 12)`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
 	t.Run("invalid", func(t *testing.T) {
 		id, err := ObjectIDHex("1234")
-		expected := errors.New("invalid input to ObjectIDHex: \"1234\"")
+		expected := errors.New("invalid input to ObjectIDHex: \"1234\": decoded length was 2 bytes (expected 12)")
 		if err.Error() != expected.Error() {
 			t.Fatalf("expected %v got %v", expected, err)
 		}
@@ -221,75 +543,1075 @@ func TestIsObjectIDHex(t *testing.T) {
 	})
 }
 
-func TestStringRep(t *testing.T) {
+func TestClassifyString(t *testing.T) {
 	id, err := ObjectIDHex(testID)
 	if err != nil {
 		t.Fatalf("could not make objectId %v", err)
 	}
-	expected := "ObjectID(\"" + testID + "\")"
 
-	if expected != id.String() {
-		t.Fatalf("expected %s, got %s", expected, id.String())
+	tests := []struct {
+		name string
+		in   string
+		kind string
+		ok   bool
+	}{
+		{name: "raw12", in: string(id), kind: "raw12", ok: true},
+		{name: "hex24", in: testID, kind: "hex24", ok: true},
+		{name: "empty", in: "", kind: "", ok: false},
+		{name: "short", in: "1234", kind: "", ok: false},
+		{name: "non_hex24", in: "xxxxxxxxxxxxxxxxxxxxxxxx", kind: "", ok: false},
+		{name: "wrapped", in: id.String(), kind: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, ok := ClassifyString(tt.in)
+			if kind != tt.kind || ok != tt.ok {
+				t.Fatalf("expected (%q, %v), got (%q, %v)", tt.kind, tt.ok, kind, ok)
+			}
+		})
 	}
 }
 
-func TestHex(t *testing.T) {
-	id, err := ObjectIDHex(testID)
+func TestMustObjectIDHex(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id := MustObjectIDHex(testID)
+		if !id.Valid() || id.Hex() != testID {
+			t.Fatalf("expected %s, got %v", testID, id)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := `oid: MustObjectIDHex("1234"): invalid input to ObjectIDHex: "1234": decoded length was 2 bytes (expected 12)`
+		defer func() {
+			if r := recover(); r != expected {
+				t.Fatalf("expected panic %s, got %v", expected, r)
+			}
+		}()
+		MustObjectIDHex("1234")
+	})
+}
+
+func TestNewObjectIDChecked(t *testing.T) {
+	id, err := NewObjectIDChecked()
 	if err != nil {
-		t.Fatalf("could not make objectId %v", err)
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !id.Valid() {
+		t.Fatalf("expected a valid id, got %v", id)
 	}
 
-	if testID != id.Hex() {
-		t.Fatalf("expected %s, got %s", testID, id.Hex())
+	other, err := NewObjectIDChecked()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if other == id {
+		t.Fatalf("expected distinct ids, got %v twice", id)
 	}
 }
 
-func TestTime(t *testing.T) {
+func TestNewObjectIDBytes(t *testing.T) {
+	// the direct copy must match the hex round-trip it replaced
+	for i := 0; i < 100; i++ {
+		p := primitive.NewObjectID()
+		expected, err := ObjectIDHex(p.Hex())
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+		if out := ObjectID(p[:]); out != expected {
+			t.Fatalf("expected %v, got %v", expected, out)
+		}
+	}
+
+	if id := NewObjectID(); !id.Valid() {
+		t.Fatalf("expected a valid id, got %v", id)
+	}
+}
+
+func TestIntern(t *testing.T) {
+	buf, _ := hex.DecodeString(testID)
+	// Alias buf the way a decoder reusing its scratch buffer would.
+	aliased := *(*ObjectID)(unsafe.Pointer(&buf))
+
+	interned := Intern(aliased)
+	if interned != aliased {
+		t.Fatalf("expected %v, got %v", aliased, interned)
+	}
+
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	if aliased.Hex() != "000000000000000000000000" {
+		t.Fatalf("expected the aliased id to change, got %s", aliased.Hex())
+	}
+	if interned.Hex() != testID {
+		t.Fatalf("expected %s, got %s", testID, interned.Hex())
+	}
+}
+
+func TestStringRep(t *testing.T) {
 	id, err := ObjectIDHex(testID)
 	if err != nil {
 		t.Fatalf("could not make objectId %v", err)
 	}
+	expected := "ObjectID(\"" + testID + "\")"
 
-	tValid := time.Unix(testIDSecs, 0)
-	if tValid != id.Time() {
-		t.Fatalf("could not retrieve proper time stamp from objectId")
+	if expected != id.String() {
+		t.Fatalf("expected %s, got %s", expected, id.String())
 	}
 }
 
-func TestMachine(t *testing.T) {
+func TestHex(t *testing.T) {
 	id, err := ObjectIDHex(testID)
 	if err != nil {
 		t.Fatalf("could not make objectId %v", err)
 	}
 
-	b := id.Machine()
-
-	// Machine value is 3 bytes, convert to int to compare.
-	mVal := uint32(b[2]) | uint32(b[1])<<8 | uint32(b[0])<<16
-	if mVal != testIDMachine {
-		t.Fatalf("could not retrieve proper machine ID from objectId")
+	if testID != id.Hex() {
+		t.Fatalf("expected %s, got %s", testID, id.Hex())
 	}
 }
 
-func TestPid(t *testing.T) {
-	id, err := ObjectIDHex(testID)
-	if err != nil {
-		t.Fatalf("could not make objectId %v", err)
-	}
+func TestLogString(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		if id.LogString() != testID {
+			t.Fatalf("expected %s, got %s", testID, id.LogString())
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		expected := "<empty>"
+		if ObjectID("").LogString() != expected {
+			t.Fatalf("expected %s, got %s", expected, ObjectID("").LogString())
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		expected := "<invalid:313233>"
+		if ObjectID("123").LogString() != expected {
+			t.Fatalf("expected %s, got %s", expected, ObjectID("123").LogString())
+		}
+	})
+}
+
+func TestFlag(t *testing.T) {
+	parse := func(args ...string) (ObjectID, error) {
+		var id ObjectID
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&id, "id", "target object id")
+		return id, fs.Parse(args)
+	}
+
+	t.Run("hex", func(t *testing.T) {
+		id, err := parse("-id", testID)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id.Hex() != testID {
+			t.Fatalf("expected %s, got %v", testID, id)
+		}
+	})
+
+	t.Run("string_form", func(t *testing.T) {
+		expected, _ := ObjectIDHex(testID)
+		id, err := parse("-id=" + expected.String())
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id != expected {
+			t.Fatalf("expected %v, got %v", expected, id)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		id, err := parse()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id != "" {
+			t.Fatalf("expected empty id, got %v", id)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := `invalid value "nope" for flag -id: unrecognized ObjectID format: "nope"`
+		if _, err := parse("-id", "nope"); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("unchanged_on_error", func(t *testing.T) {
+		id, _ := ObjectIDHex(testID)
+		if err := id.Set("nope"); err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+		if id.Hex() != testID {
+			t.Fatalf("expected %s, got %v", testID, id)
+		}
+	})
+}
+
+func TestAppendHex(t *testing.T) {
+	ids := []ObjectID{"", ObjectID("123"), ObjectID("\x00\xff\x10\x9a")}
+	for i := 0; i < 100; i++ {
+		ids = append(ids, NewObjectID())
+	}
+	for _, id := range ids {
+		expected := hex.EncodeToString([]byte(id))
+		if id.Hex() != expected {
+			t.Fatalf("expected %s, got %s", expected, id.Hex())
+		}
+		if out := id.AppendHex([]byte("id=")); string(out) != "id="+expected {
+			t.Fatalf("expected id=%s, got %s", expected, out)
+		}
+	}
+
+	t.Run("no_allocs", func(t *testing.T) {
+		id := NewObjectID()
+		buf := make([]byte, 0, 24)
+		if n := testing.AllocsPerRun(100, func() { buf = id.AppendHex(buf[:0]) }); n != 0 {
+			t.Fatalf("expected 0 allocations, got %v", n)
+		}
+	})
+}
+
+func TestHexArray(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		a, err := id.HexArray()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if string(a[:]) != testID {
+			t.Fatalf("expected %s, got %s", testID, a[:])
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "invalid ObjectID: \"123\""
+		if _, err := ObjectID("123").HexArray(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestExternalKey(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(strings.ToUpper(testID))
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		key, err := id.ExternalKey()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if key != testID {
+			t.Fatalf("expected %s, got %s", testID, key)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if _, err := ObjectID("123").ExternalKey(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestIndexKey(t *testing.T) {
+	t.Run("ordering", func(t *testing.T) {
+		now := time.Unix(testIDSecs, 0)
+		NowFunc = func() time.Time { return now }
+		t.Cleanup(func() { NowFunc = time.Now })
+
+		g := NewGenerator()
+		g.counter = 0xfffa // carry into the high byte of the counter
+		var ids []ObjectID
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 10; j++ {
+				ids = append(ids, g.New())
+			}
+			now = now.Add(time.Second)
+			g.counter = 0
+		}
+
+		for i := 1; i < len(ids); i++ {
+			prev, err := ids[i-1].IndexKey()
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			cur, err := ids[i].IndexKey()
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if bytes.Compare(prev, cur) >= 0 {
+				t.Fatalf("expected %x to sort before %x", prev, cur)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		key, err := ObjectID("123").IndexKey()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+		if key != nil {
+			t.Fatalf("expected nil key, got %x", key)
+		}
+	})
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	id, _ := ObjectIDHex(testID)
+	carry, _ := ObjectIDHex("5d6f6ff1646327ce3196ffff")
+
+	tests := []struct {
+		name     string
+		prefix   []byte
+		expected string
+	}{
+		{"simple", []byte{0x5d, 0x6f}, "5d7000000000000000000000"},
+		{"carry", []byte{0x5d, 0x6f, 0xff, 0xff}, "5d7000000000000000000000"},
+		{"full", []byte(id), "5d6f6ff1646327ce31968d94"},
+		{"full_carry", []byte(carry), "5d6f6ff1646327ce31970000"},
+		{"too_long", append([]byte(id), 0xff), "5d6f6ff1646327ce31968d94"},
+		{"all_ff", []byte{0xff, 0xff}, ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := PrefixUpperBound(tt.prefix); out.Hex() != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, out.Hex())
+			}
+		})
+	}
+
+	t.Run("bounds_scan", func(t *testing.T) {
+		prefix := []byte{0x5d, 0x6f, 0xff}
+		upper := PrefixUpperBound(prefix)
+		last := ObjectID(append(prefix, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff))
+		if last >= upper {
+			t.Fatalf("expected %v to sort before %v", last, upper)
+		}
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		key, err := id.CacheKey("user")
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if key != "user:"+testID {
+			t.Fatalf("expected user:%s, got %s", testID, key)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		key, err := ObjectID("123").CacheKey("user")
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+		if key != "" {
+			t.Fatalf("expected empty key, got %s", key)
+		}
+	})
+}
+
+func TestEqualHexString(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		if !id.EqualHexString(testID) {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("mixed_case", func(t *testing.T) {
+		if !id.EqualHexString("5D6f6FF1646327CE31968d93") {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("different", func(t *testing.T) {
+		if id.EqualHexString("5d6f6ff1646327ce31968d94") {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, s := range []string{"", "1234", testID + "00", "5d6f6ff1646327ce31968d9x"} {
+			if id.EqualHexString(s) {
+				t.Fatalf("expected false for %q, got true", s)
+			}
+		}
+		if ObjectID("123").EqualHexString(testID) {
+			t.Fatalf("expected false for invalid id, got true")
+		}
+	})
+}
+
+func BenchmarkEqualHexString(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	s := strings.ToUpper(testID)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.EqualHexString(s)
+	}
+}
+
+func BenchmarkHexEquality(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	s := testID
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.Hex() == s
+	}
+}
+
+// hexSink keeps the results of the hex benchmarks alive so their allocations
+// are not optimized away.
+var hexSink string
+
+func BenchmarkHex(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hexSink = id.Hex()
+	}
+}
+
+func BenchmarkHexStdlib(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hexSink = hex.EncodeToString([]byte(id))
+	}
+}
+
+func BenchmarkAppendHex(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	buf := make([]byte, 0, 24)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = id.AppendHex(buf[:0])
+	}
+}
+
+func BenchmarkHexArray(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = id.HexArray()
+	}
+}
+
+func BenchmarkMarshalBSONValue(b *testing.B) {
+	id, _ := ObjectIDHex(testID)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = id.MarshalBSONValue()
+	}
+}
+
+func TestMetricLabel(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		if id.MetricLabel() != testID {
+			t.Fatalf("expected %s, got %s", testID, id.MetricLabel())
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, id := range []ObjectID{"", "123", ObjectID(strings.Repeat("\x00", 13))} {
+			if id.MetricLabel() != "invalid" {
+				t.Fatalf("expected invalid, got %s", id.MetricLabel())
+			}
+		}
+	})
+}
+
+func TestTime(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	tValid := time.Unix(testIDSecs, 0)
+	if tValid != id.Time() {
+		t.Fatalf("could not retrieve proper time stamp from objectId")
+	}
+}
+
+func TestPseudonymize(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	salt := []byte("salt-a")
+
+	p := id.Pseudonymize(salt)
+	if len(p) != 24 || !IsObjectIDHex(p) {
+		t.Fatalf("expected 24 hex characters, got %s", p)
+	}
+	if p == testID {
+		t.Fatalf("expected pseudonym to differ from the id, got %s", p)
+	}
+	if again := id.Pseudonymize([]byte("salt-a")); again != p {
+		t.Fatalf("expected %s, got %s", p, again)
+	}
+	if other := id.Pseudonymize([]byte("salt-b")); other == p {
+		t.Fatalf("expected different salts to diverge, got %s for both", p)
+	}
+	if other := NewObjectID().Pseudonymize(salt); other == p {
+		t.Fatalf("expected different ids to diverge, got %s for both", p)
+	}
+}
+
+func TestObfuscate(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	key := [16]byte{0: 1, 15: 2}
+
+	t.Run("round_trip", func(t *testing.T) {
+		s := id.Obfuscate(key)
+		if s == "" || strings.Contains(s, testID) {
+			t.Fatalf("expected an obfuscated id, got %q", s)
+		}
+		if again := id.Obfuscate(key); again != s {
+			t.Fatalf("expected %s, got %s", s, again)
+		}
+
+		out, err := DeobfuscateObjectID(s, key)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("hides_order", func(t *testing.T) {
+		next, _ := id.NextWithOverflow()
+		a, b := id.Obfuscate(key), next.Obfuscate(key)
+		if a[:8] == b[:8] {
+			t.Fatalf("expected adjacent ids to diverge, got %s and %s", a, b)
+		}
+	})
+
+	t.Run("wrong_key", func(t *testing.T) {
+		s := id.Obfuscate(key)
+		expected := fmt.Sprintf("invalid obfuscated ObjectID: %q", s)
+		_, err := DeobfuscateObjectID(s, [16]byte{0: 1, 15: 3})
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, s := range []string{"", "not base64!", "c2hvcnQ"} {
+			expected := fmt.Sprintf("invalid obfuscated ObjectID: %q", s)
+			if _, err := DeobfuscateObjectID(s, key); err == nil || err.Error() != expected {
+				t.Fatalf("expected %s, got %v", expected, err)
+			}
+		}
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		if s := ObjectID("123").Obfuscate(key); s != "" {
+			t.Fatalf("expected empty string, got %s", s)
+		}
+	})
+}
+
+func TestHashInto(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	a := make([]uint64, 4)
+	b := make([]uint64, 4)
+	id.HashInto(a)
+	Intern(id).HashInto(b)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("expected %v, got %v", a, b)
+	}
+	for i := 1; i < len(a); i++ {
+		if a[i] == a[0] {
+			t.Fatalf("expected distinct hashes, got %v", a)
+		}
+	}
+
+	other := make([]uint64, 4)
+	NewObjectID().HashInto(other)
+	if reflect.DeepEqual(a, other) {
+		t.Fatalf("expected distinct ids to hash differently, got %v for both", a)
+	}
+
+	// an empty slice is left alone
+	id.HashInto(nil)
+}
+
+func TestIsZero(t *testing.T) {
+	zero, err := ObjectIDHex("000000000000000000000000")
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	var p primitive.ObjectID
+
+	tests := []struct {
+		name     string
+		id       ObjectID
+		expected bool
+	}{
+		{"nil", NilObjectID, true},
+		{"zero_bytes", zero, true},
+		{"zero_primitive", ObjectID(p[:]), true},
+		{"valid", id, false},
+		{"malformed", ObjectID("123"), false},
+		{"malformed_zero_bytes", ObjectID("\x00\x00\x00"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.id.IsZero() != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, tt.id.IsZero())
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	other := NewObjectID()
+
+	tests := []struct {
+		name   string
+		a, b   ObjectID
+		equal  bool
+		secure bool
+	}{
+		{"equal", id, Intern(id), true, true},
+		{"unequal", id, other, false, false},
+		{"empty_empty", "", "", true, false},
+		{"empty_valid", "", id, false, false},
+		{"malformed", ObjectID("123"), ObjectID("123"), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a.Equal(tt.b) != tt.equal {
+				t.Fatalf("expected Equal %v, got %v", tt.equal, tt.a.Equal(tt.b))
+			}
+			if tt.a.SecureEqual(tt.b) != tt.secure {
+				t.Fatalf("expected SecureEqual %v, got %v", tt.secure, tt.a.SecureEqual(tt.b))
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	next, _ := id.NextWithOverflow()
+	later, _ := ObjectIDHex("5d6f6ff20000000000000000")
+
+	tests := []struct {
+		name     string
+		a, b     ObjectID
+		expected int
+	}{
+		{"equal", id, Intern(id), 0},
+		{"counter", id, next, -1},
+		{"counter_reversed", next, id, 1},
+		{"timestamp", later, id, 1},
+		{"empty_empty", "", "", 0},
+		{"empty_valid", "", id, -1},
+		{"malformed", ObjectID("\xff"), id, 1},
+		{"prefix", id[:6], id, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c := tt.a.Compare(tt.b); c != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, c)
+			}
+		})
+	}
+}
+
+func TestCheckedAccessors(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		ts, err := id.TimeChecked()
+		if err != nil || !ts.Equal(time.Unix(testIDSecs, 0)) {
+			t.Fatalf("expected %v, got %v (%v)", time.Unix(testIDSecs, 0), ts, err)
+		}
+		m, err := id.MachineChecked()
+		if err != nil || !bytes.Equal(m, id.Machine()) {
+			t.Fatalf("expected %x, got %x (%v)", id.Machine(), m, err)
+		}
+		p, err := id.PidChecked()
+		if err != nil || p != id.Pid() {
+			t.Fatalf("expected %d, got %d (%v)", id.Pid(), p, err)
+		}
+		c, err := id.CounterChecked()
+		if err != nil || c != testIDCounter {
+			t.Fatalf("expected %d, got %d (%v)", testIDCounter, c, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		id := ObjectID("123")
+		expected := `invalid ObjectID: "123"`
+
+		errs := map[string]error{}
+		_, errs["time"] = id.TimeChecked()
+		_, errs["machine"] = id.MachineChecked()
+		_, errs["pid"] = id.PidChecked()
+		_, errs["counter"] = id.CounterChecked()
+		for name, err := range errs {
+			if err == nil || err.Error() != expected {
+				t.Fatalf("%s: expected %s, got %v", name, expected, err)
+			}
+		}
+	})
+}
+
+func TestHasZeroTimestamp(t *testing.T) {
+	t.Run("zero", func(t *testing.T) {
+		id, _ := ObjectIDHex("00000000646327ce31968d93")
+		if !id.HasZeroTimestamp() {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("normal", func(t *testing.T) {
+		id, _ := ObjectIDHex(testID)
+		if id.HasZeroTimestamp() {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if ObjectID("\x00\x00\x00\x00").HasZeroTimestamp() || ObjectID("").HasZeroTimestamp() {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestMachine(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	b := id.Machine()
+
+	// Machine value is 3 bytes, convert to int to compare.
+	mVal := uint32(b[2]) | uint32(b[1])<<8 | uint32(b[0])<<16
+	if mVal != testIDMachine {
+		t.Fatalf("could not retrieve proper machine ID from objectId")
+	}
+}
+
+func TestPid(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	if id.Pid() != testIDPid {
+		t.Fatalf("could not retrieve proper PID from objectId")
+	}
+}
+
+func TestCounter(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	if id.Counter() != testIDCounter {
+		t.Fatalf("could not retrieve proper counter from objectId")
+	}
+}
+
+func TestCounterBytes(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		b, err := id.CounterBytes()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		expected := []byte{0x96, 0x8d, 0x93}
+		if !bytes.Equal(b, expected) {
+			t.Fatalf("expected %x, got %x", expected, b)
+		}
+		if int32(uint32(b[0])<<16|uint32(b[1])<<8|uint32(b[2])) != testIDCounter {
+			t.Fatalf("could not retrieve proper counter bytes from objectId")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "invalid ObjectID: \"123\""
+		if _, err := ObjectID("123").CounterBytes(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestNextWithOverflow(t *testing.T) {
+	t.Run("increment", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		next, overflow := id.NextWithOverflow()
+		if overflow {
+			t.Fatalf("expected no overflow")
+		}
+		if next.Counter() != testIDCounter+1 {
+			t.Fatalf("expected counter %d, got %d", testIDCounter+1, next.Counter())
+		}
+		if next.Time() != id.Time() {
+			t.Fatalf("expected time to be unchanged, got %v", next.Time())
+		}
+	})
+
+	t.Run("carry", func(t *testing.T) {
+		id, _ := ObjectIDHex("5d6f6ff1646327ce3100ffff")
+		next, overflow := id.NextWithOverflow()
+		if overflow {
+			t.Fatalf("expected no overflow")
+		}
+		if next.Hex() != "5d6f6ff1646327ce31010000" {
+			t.Fatalf("expected 5d6f6ff1646327ce31010000, got %s", next.Hex())
+		}
+	})
+
+	t.Run("counter_max", func(t *testing.T) {
+		id, _ := ObjectIDHex("5d6f6ff1646327ce31ffffff")
+		next, overflow := id.NextWithOverflow()
+		if !overflow {
+			t.Fatalf("expected overflow")
+		}
+		if next.Hex() != "5d6f6ff1646327ce31000000" {
+			t.Fatalf("expected 5d6f6ff1646327ce31000000, got %s", next.Hex())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		next, overflow := ObjectID("123").NextWithOverflow()
+		if next != "" || overflow {
+			t.Fatalf("expected empty id and no overflow, got %v %v", next, overflow)
+		}
+	})
+}
+
+func TestCounterDistance(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("same_second", func(t *testing.T) {
+		earlier, _ := ObjectIDHex("5d6f6ff1646327ce31968d80")
+		d, ok := id.CounterDistance(earlier)
+		if !ok || d != 0x13 {
+			t.Fatalf("expected (19, true), got (%d, %v)", d, ok)
+		}
+
+		d, ok = earlier.CounterDistance(id)
+		if !ok || d != -0x13 {
+			t.Fatalf("expected (-19, true), got (%d, %v)", d, ok)
+		}
+	})
+
+	t.Run("cross_second", func(t *testing.T) {
+		later, _ := ObjectIDHex("5d6f6ff2646327ce31968d93")
+		if _, ok := id.CounterDistance(later); ok {
+			t.Fatalf("expected ok to be false")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, ok := id.CounterDistance(ObjectID("123")); ok {
+			t.Fatalf("expected ok to be false")
+		}
+	})
+}
+
+func TestCoGenerated(t *testing.T) {
+	setNow(t, time.Unix(testIDSecs, 0))
+	g := NewGenerator()
+	a, b := g.New(), g.New()
+
+	t.Run("same_process_same_second", func(t *testing.T) {
+		if !a.CoGenerated(b) || !b.CoGenerated(a) {
+			t.Fatalf("expected %v and %v to be co-generated", a, b)
+		}
+	})
+
+	t.Run("different_random", func(t *testing.T) {
+		other := NewGenerator().New()
+		if a.CoGenerated(other) {
+			t.Fatalf("expected %v and %v not to be co-generated", a, other)
+		}
+	})
+
+	t.Run("different_second", func(t *testing.T) {
+		later, err := b.WithTimestamp(time.Unix(testIDSecs+1, 0))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if a.CoGenerated(later) {
+			t.Fatalf("expected %v and %v not to be co-generated", a, later)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if a.CoGenerated(ObjectID("123")) || ObjectID("").CoGenerated("") {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestEqualIgnoringTime(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("same_suffix_different_time", func(t *testing.T) {
+		retried, err := id.WithTimestamp(id.Time().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if !id.EqualIgnoringTime(retried) || !retried.EqualIgnoringTime(id) {
+			t.Fatalf("expected %v and %v to be equal ignoring time", id, retried)
+		}
+	})
+
+	t.Run("different", func(t *testing.T) {
+		other := NewObjectID()
+		if id.EqualIgnoringTime(other) {
+			t.Fatalf("expected %v and %v not to be equal ignoring time", id, other)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if id.EqualIgnoringTime(ObjectID("123")) || ObjectID("").EqualIgnoringTime("") {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestBetween(t *testing.T) {
+	id, _ := ObjectIDHex(testID)
+	lo, _ := ObjectIDHex("5d6f6ff10000000000000000")
+	hi, _ := ObjectIDHex("5d6f6ff1ffffffffffffffff")
+
+	t.Run("inside", func(t *testing.T) {
+		if !id.Between(lo, hi) {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("on_boundary", func(t *testing.T) {
+		if !lo.Between(lo, hi) || !hi.Between(lo, hi) {
+			t.Fatalf("expected bounds to be inclusive")
+		}
+	})
 
-	if id.Pid() != testIDPid {
-		t.Fatalf("could not retrieve proper PID from objectId")
-	}
+	t.Run("outside", func(t *testing.T) {
+		later, _ := ObjectIDHex("5d6f6ff20000000000000000")
+		if later.Between(lo, hi) || id.Between(hi, hi) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if ObjectID("123").Between(lo, hi) || id.Between(ObjectID(""), hi) || id.Between(lo, ObjectID("123")) {
+			t.Fatalf("expected false, got true")
+		}
+	})
 }
 
-func TestCounter(t *testing.T) {
-	id, err := ObjectIDHex(testID)
-	if err != nil {
-		t.Fatalf("could not make objectId %v", err)
+func TestPositionIn(t *testing.T) {
+	lo, _ := ObjectIDHex("5d6f6ff10000000000000000")
+	hi, _ := ObjectIDHex("5d6f6ff10000000000000064")
+	mid, _ := ObjectIDHex("5d6f6ff10000000000000032")
+	quarter, _ := ObjectIDHex("5d6f6ff10000000000000019")
+
+	tests := []struct {
+		name     string
+		id       ObjectID
+		min, max ObjectID
+		expected float64
+	}{
+		{"min", lo, lo, hi, 0},
+		{"max", hi, lo, hi, 1},
+		{"midpoint", mid, lo, hi, 0.5},
+		{"quarter", quarter, lo, hi, 0.25},
+		{"below", MinObjectIDForTime(time.Unix(0, 0)), lo, hi, 0},
+		{"above", MaxObjectIDForTime(time.Unix(testIDSecs+1, 0)), lo, hi, 1},
+		{"time_range", MinObjectIDForTime(time.Unix(testIDSecs+50, 0)), MinObjectIDForTime(time.Unix(testIDSecs, 0)), MinObjectIDForTime(time.Unix(testIDSecs+100, 0)), 0.5},
+		{"invalid_id", ObjectID("123"), lo, hi, 0},
+		{"invalid_bound", mid, "", hi, 0},
+		{"empty_range", mid, hi, lo, 0},
 	}
-
-	if id.Counter() != testIDCounter {
-		t.Fatalf("could not retrieve proper counter from objectId")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p := tt.id.PositionIn(tt.min, tt.max); p != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, p)
+			}
+		})
 	}
 }
 
@@ -325,12 +1647,26 @@ func TestJSON(t *testing.T) {
 
 		var out test
 
-		expected := "invalid ObjectID in JSON: {\"$oid\":123} (encoding/hex: invalid byte: U+007B '{')"
+		expected := "not an extended JSON ObjectID"
 		if err := json.Unmarshal(b, &out); err.Error() != expected {
 			t.Fatalf("expected %s, got %v", expected, err)
 		}
 	})
 
+	t.Run("short_hex", func(t *testing.T) {
+		// 12 hex characters only make 6 bytes and must not yield a half-filled id
+		for _, in := range []string{`"abcdef012345"`, `"abcdef0123"`} {
+			var id ObjectID
+			expected := "invalid ObjectID in JSON: " + strings.Trim(in, `"`)
+			if err := json.Unmarshal([]byte(in), &id); err == nil || err.Error() != expected {
+				t.Fatalf("expected %s, got %v", expected, err)
+			}
+			if id != "" {
+				t.Fatalf("expected empty id, got %v", id)
+			}
+		}
+	})
+
 	t.Run("extended_JSON_success", func(t *testing.T) {
 		p := map[string]interface{}{
 			"v": map[string]interface{}{
@@ -350,6 +1686,49 @@ func TestJSON(t *testing.T) {
 		}
 	})
 
+	t.Run("id_key_success", func(t *testing.T) {
+		p := map[string]interface{}{
+			"v": map[string]interface{}{
+				"id": testID,
+			},
+		}
+
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		var out test
+
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		if out.V.Hex() != testID {
+			t.Fatalf("expected %s, got %s", testID, out.V.Hex())
+		}
+	})
+
+	t.Run("id_key_invalid_field", func(t *testing.T) {
+		p := map[string]interface{}{
+			"v": map[string]interface{}{
+				"id": 234123,
+			},
+		}
+
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		var out test
+
+		expected := "not an extended JSON ObjectID"
+		if err := json.Unmarshal(b, &out); err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
 	t.Run("extended_JSON_invalid_field", func(t *testing.T) {
 		p := map[string]interface{}{
 			"v": map[string]interface{}{
@@ -447,6 +1826,48 @@ func TestJSON(t *testing.T) {
 		}
 	})
 
+	t.Run("null", func(t *testing.T) {
+		id := NewObjectID()
+		if err := json.Unmarshal([]byte("null"), &id); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id != "" {
+			t.Fatalf("expected empty id, got %v", id)
+		}
+	})
+
+	t.Run("null_field", func(t *testing.T) {
+		out := test{V: NewObjectID()}
+		if err := json.Unmarshal([]byte(`{"v":null}`), &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V != "" {
+			t.Fatalf("expected empty id, got %v", out.V)
+		}
+	})
+
+	t.Run("null_extended_JSON", func(t *testing.T) {
+		out := test{V: NewObjectID()}
+		if err := json.Unmarshal([]byte(`{"v":{"$oid":null}}`), &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V != "" {
+			t.Fatalf("expected empty id, got %v", out.V)
+		}
+	})
+
+	t.Run("empty_extended_JSON", func(t *testing.T) {
+		for _, in := range []string{`{"v":{"$oid":""}}`, `{"v":{"id":""}}`} {
+			out := test{V: NewObjectID()}
+			if err := json.Unmarshal([]byte(in), &out); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out.V != "" {
+				t.Fatalf("expected empty id, got %v", out.V)
+			}
+		}
+	})
+
 	t.Run("hex", func(t *testing.T) {
 		p := map[string]interface{}{
 			"v": "xxxxxxxxxxxxxxxxxxxxxxxx",
@@ -465,6 +1886,33 @@ func TestJSON(t *testing.T) {
 		}
 	})
 
+	t.Run("uppercase", func(t *testing.T) {
+		upper := strings.ToUpper(testID)
+		var lowerOut, upperOut ObjectID
+
+		if err := json.Unmarshal([]byte(`"`+testID+`"`), &lowerOut); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if err := json.Unmarshal([]byte(`"`+upper+`"`), &upperOut); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		if upperOut != lowerOut {
+			t.Fatalf("expected equality, got %v and %v", upperOut, lowerOut)
+		}
+		if upperOut.Hex() != testID {
+			t.Fatalf("expected %s, got %s", testID, upperOut.Hex())
+		}
+
+		b, err := json.Marshal(upperOut)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if string(b) != `"`+testID+`"` {
+			t.Fatalf("expected %q, got %s", testID, b)
+		}
+	})
+
 	t.Run("marshal", func(t *testing.T) {
 		p := test{
 			V: NewObjectID(),
@@ -496,6 +1944,287 @@ func TestJSON(t *testing.T) {
 	})
 }
 
+func TestText(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("marshal", func(t *testing.T) {
+		b, err := id.MarshalText()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if string(b) != testID {
+			t.Fatalf("expected %s, got %s", testID, b)
+		}
+
+		b, err = ObjectID("").MarshalText()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(b) != 0 {
+			t.Fatalf("expected empty text, got %s", b)
+		}
+
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if _, err := ObjectID("123").MarshalText(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			in       string
+			expected ObjectID
+		}{
+			{"hex", testID, id},
+			{"uppercase", strings.ToUpper(testID), id},
+			{"string_form", id.String(), id},
+			{"empty", "", ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				out := NewObjectID()
+				if err := out.UnmarshalText([]byte(tt.in)); err != nil {
+					t.Fatalf("expected nil, got %v", err)
+				}
+				if out != tt.expected {
+					t.Fatalf("expected %v, got %v", tt.expected, out)
+				}
+			})
+		}
+	})
+
+	t.Run("unmarshal_invalid", func(t *testing.T) {
+		tests := []struct {
+			in       string
+			expected string
+		}{
+			{"123", `invalid input to ObjectIDHex: "123"`},
+			{"xxxxxxxxxxxxxxxxxxxxxxxx", `invalid input to ObjectIDHex: "xxxxxxxxxxxxxxxxxxxxxxxx"`},
+			{`ObjectID("123")`, `invalid input to ObjectIDHex: "123"`},
+			{`ObjectId("` + testID + `")`, `invalid input to ObjectIDHex: "ObjectId(\"` + testID + `\")"`},
+		}
+		for _, tt := range tests {
+			out := id
+			if err := out.UnmarshalText([]byte(tt.in)); err == nil || err.Error() != tt.expected {
+				t.Fatalf("expected %s, got %v", tt.expected, err)
+			}
+			if out != id {
+				t.Fatalf("expected %v to be unchanged, got %v", id, out)
+			}
+		}
+	})
+
+	t.Run("json_map_key", func(t *testing.T) {
+		var m map[ObjectID]int
+		if err := json.Unmarshal([]byte(`{"`+testID+`":1}`), &m); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if m[id] != 1 {
+			t.Fatalf("expected map[%v:1], got %v", id, m)
+		}
+	})
+}
+
+func TestBinary(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		b, err := id.MarshalBinary()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if hex.EncodeToString(b) != testID {
+			t.Fatalf("expected %s, got %x", testID, b)
+		}
+
+		var out ObjectID
+		if err := out.UnmarshalBinary(b); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("gob", func(t *testing.T) {
+		type rec struct {
+			ID    ObjectID
+			Empty ObjectID
+			Name  string
+		}
+		in := rec{ID: id, Name: "x"}
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		var out rec
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != in {
+			t.Fatalf("expected %+v, got %+v", in, out)
+		}
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if _, err := ObjectID("123").MarshalBinary(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("wrong_length", func(t *testing.T) {
+		out := id
+		expected := "invalid ObjectID length: expected 12 bytes, got 3"
+		if err := out.UnmarshalBinary([]byte("123")); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+		if out != id {
+			t.Fatalf("expected %v to be unchanged, got %v", id, out)
+		}
+	})
+}
+
+func TestJSONFormat(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	defer func() { JSONFormat = JSONHex }()
+
+	tests := []struct {
+		name  string
+		mode  JSONMode
+		id    string
+		empty string
+	}{
+		{name: "hex", mode: JSONHex, id: `"` + testID + `"`, empty: `""`},
+		{name: "extended", mode: JSONExtended, id: `{"$oid":"` + testID + `"}`, empty: `null`},
+		{name: "null", mode: JSONNull, id: `"` + testID + `"`, empty: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			JSONFormat = tt.mode
+
+			b, err := json.Marshal(id)
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if string(b) != tt.id {
+				t.Fatalf("expected %s, got %s", tt.id, b)
+			}
+
+			var out ObjectID
+			if err := json.Unmarshal(b, &out); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != id {
+				t.Fatalf("expected %v, got %v", id, out)
+			}
+
+			b, err = json.Marshal(ObjectID(""))
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if string(b) != tt.empty {
+				t.Fatalf("expected %s, got %s", tt.empty, b)
+			}
+		})
+	}
+}
+
+func TestMarshalExtendedJSON(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("extended", func(t *testing.T) {
+		expected := `{"$oid":"` + testID + `"}`
+		b, err := id.MarshalExtendedJSON()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if string(b) != expected {
+			t.Fatalf("expected %s, got %s", expected, b)
+		}
+
+		var out ObjectID
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out != id {
+			t.Fatalf("expected %v, got %v", id, out)
+		}
+	})
+
+	t.Run("hex_unaffected", func(t *testing.T) {
+		expected := `"` + testID + `"`
+		b, err := json.Marshal(id)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if string(b) != expected {
+			t.Fatalf("expected %s, got %s", expected, b)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		b, err := ObjectID("").MarshalExtendedJSON()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if string(b) != "null" {
+			t.Fatalf("expected null, got %s", b)
+		}
+	})
+}
+
+func TestWithTimeJSON(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		b, err := id.WithTimeJSON()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		expected := `{"id":"` + testID + `","createdAt":"2019-09-04T08:04:01Z"}`
+		if string(b) != expected {
+			t.Fatalf("expected %s, got %s", expected, b)
+		}
+
+		var out struct {
+			CreatedAt time.Time `json:"createdAt"`
+		}
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if !out.CreatedAt.Equal(id.Time()) {
+			t.Fatalf("expected %v, got %v", id.Time(), out.CreatedAt)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if _, err := ObjectID("123").WithTimeJSON(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
 func tearUp(t *testing.T, fn func(ctx context.Context, coll *mongo.Collection)) {
 	mgoAddr := os.Getenv("MONGO_ADDR")
 	if mgoAddr == "" {
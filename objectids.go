@@ -0,0 +1,226 @@
+package oid
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// ObjectIDs is a slice of ObjectID values.
+type ObjectIDs []ObjectID
+
+// Len implements sort.Interface.
+func (ids ObjectIDs) Len() int { return len(ids) }
+
+// Less implements sort.Interface, ordering ids by Compare.
+func (ids ObjectIDs) Less(i, j int) bool { return ids[i].Compare(ids[j]) < 0 }
+
+// Swap implements sort.Interface.
+func (ids ObjectIDs) Swap(i, j int) { ids[i], ids[j] = ids[j], ids[i] }
+
+// ObjectIDSet is a set of ObjectIDs keyed by their raw bytes.
+type ObjectIDSet map[ObjectID]struct{}
+
+// NewObjectIDSet returns a set holding ids.
+func NewObjectIDSet(ids ...ObjectID) ObjectIDSet {
+	set := make(ObjectIDSet, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// InSet reports whether id is a member of set.
+func (id ObjectID) InSet(set ObjectIDSet) bool {
+	_, ok := set[id]
+	return ok
+}
+
+// AllSameSecond reports whether every id in ids carries the same 4-byte
+// timestamp. It returns false if any id is invalid and true for an empty slice.
+// It is intended as a testing aid for ids generated in quick succession.
+func AllSameSecond(ids []ObjectID) bool {
+	for _, id := range ids {
+		if !id.Valid() || id[:4] != ids[0][:4] {
+			return false
+		}
+	}
+	return true
+}
+
+// LongestSameSecondRun returns the length of the longest run of consecutive
+// ids in a sorted slice that share the same timestamp. An unusually long run
+// can point at a stuck clock. Invalid ids break a run and are not counted.
+func LongestSameSecondRun(ids ObjectIDs) int {
+	longest, run := 0, 0
+	for i, id := range ids {
+		switch {
+		case !id.Valid():
+			run = 0
+		case run > 0 && ids[i-1][:4] == id[:4]:
+			run++
+		default:
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+// EstimateRate estimates how many ids were created per second from a time
+// sorted slice, dividing the number of ids by the seconds between the first and
+// the last. Timestamps only have second precision, so the estimate is rough for
+// short spans. ok is false if any id is invalid or the span is not positive.
+func EstimateRate(ids ObjectIDs) (perSecond float64, ok bool) {
+	for _, id := range ids {
+		if !id.Valid() {
+			return 0, false
+		}
+	}
+	if len(ids) < 2 {
+		return 0, false
+	}
+
+	span := ids[len(ids)-1].Time().Sub(ids[0].Time()).Seconds()
+	if span <= 0 {
+		return 0, false
+	}
+	return float64(len(ids)) / span, true
+}
+
+// Summary describes a batch of ids. See Summarize.
+type Summary struct {
+	// Count is the number of ids in the batch, including invalid ones.
+	Count int
+	// UniqueCount is the number of distinct valid ids.
+	UniqueCount int
+	// MinTime and MaxTime are the earliest and latest creation times of the
+	// valid ids. They are zero if the batch has no valid ids.
+	MinTime time.Time
+	MaxTime time.Time
+	// Invalid is the number of ids that are not valid ObjectIDs.
+	Invalid int
+}
+
+// Summarize computes a Summary of ids in a single pass, giving a quick picture
+// of the time span and data quality of an imported batch. Invalid ids are only
+// counted in Count and Invalid.
+func Summarize(ids ObjectIDs) Summary {
+	s := Summary{Count: len(ids)}
+	seen := make(map[ObjectID]struct{}, len(ids))
+	for _, id := range ids {
+		if !id.Valid() {
+			s.Invalid++
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		t := id.Time()
+		if s.MinTime.IsZero() || t.Before(s.MinTime) {
+			s.MinTime = t
+		}
+		if t.After(s.MaxTime) {
+			s.MaxTime = t
+		}
+	}
+	s.UniqueCount = len(seen)
+	return s
+}
+
+// WithTimes pairs each id with its creation time, for audit logs of inserted
+// batches. The result has one element per id in the same order; invalid ids
+// get a zero CreatedAt.
+func (ids ObjectIDs) WithTimes() []struct {
+	ID        ObjectID
+	CreatedAt time.Time
+} {
+	out := make([]struct {
+		ID        ObjectID
+		CreatedAt time.Time
+	}, len(ids))
+	for i, id := range ids {
+		out[i].ID = id
+		if id.Valid() {
+			out[i].CreatedAt = id.Time()
+		}
+	}
+	return out
+}
+
+// Entry is a key and value pair from a map keyed by ObjectID.
+type Entry[T any] struct {
+	ID  ObjectID
+	Val T
+}
+
+// SortedEntries returns the entries of m sorted by the raw bytes of their ids,
+// giving a stable order for golden-file comparisons of id keyed maps.
+func SortedEntries[T any](m map[ObjectID]T) []Entry[T] {
+	entries := make([]Entry[T], 0, len(m))
+	for id, v := range m {
+		entries = append(entries, Entry[T]{ID: id, Val: v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries
+}
+
+// KMergeSorted merges any number of slices, each already sorted in ascending
+// byte order, into a single sorted slice. It is meant for assembling results
+// from several _id ordered cursors, one per shard. The inputs must be sorted;
+// unsorted input produces an unspecified order. Duplicates are kept, see
+// KMergeSortedUnique.
+func KMergeSorted(streams ...ObjectIDs) ObjectIDs {
+	return kMerge(streams, false)
+}
+
+// KMergeSortedUnique is like KMergeSorted but keeps only the first of any run
+// of equal ids, across as well as within streams.
+func KMergeSortedUnique(streams ...ObjectIDs) ObjectIDs {
+	return kMerge(streams, true)
+}
+
+func kMerge(streams []ObjectIDs, unique bool) ObjectIDs {
+	total := 0
+	h := make(mergeHeap, 0, len(streams))
+	for _, s := range streams {
+		total += len(s)
+		if len(s) > 0 {
+			h = append(h, s)
+		}
+	}
+	heap.Init(&h)
+
+	out := make(ObjectIDs, 0, total)
+	for h.Len() > 0 {
+		id := h[0][0]
+		if !unique || len(out) == 0 || out[len(out)-1] != id {
+			out = append(out, id)
+		}
+
+		if h[0] = h[0][1:]; len(h[0]) == 0 {
+			heap.Pop(&h)
+		} else {
+			heap.Fix(&h, 0)
+		}
+	}
+	return out
+}
+
+// mergeHeap is a min-heap of non-empty streams ordered by their first id.
+type mergeHeap []ObjectIDs
+
+func (h mergeHeap) Len() int            { return len(h) }
+func (h mergeHeap) Less(i, j int) bool  { return h[i][0] < h[j][0] }
+func (h mergeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(ObjectIDs)) }
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,283 @@
+package oid
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSortObjectIDs(t *testing.T) {
+	now := time.Unix(testIDSecs, 0)
+	NowFunc = func() time.Time { return now }
+	t.Cleanup(func() { NowFunc = time.Now })
+
+	g := NewGenerator()
+	g.counter = 0
+	var expected ObjectIDs
+	for i := 0; i < 20; i++ {
+		expected = append(expected, g.New())
+		if i%3 == 0 {
+			now = now.Add(time.Second)
+		}
+	}
+
+	ids := make(ObjectIDs, len(expected))
+	for i, j := range rand.Perm(len(expected)) {
+		ids[i] = expected[j]
+	}
+	sort.Sort(ids)
+
+	if !reflect.DeepEqual(expected, ids) {
+		t.Fatalf("\nexpected: %v \n got %v", expected, ids)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i].Time().Before(ids[i-1].Time()) {
+			t.Fatalf("expected timestamp order, got %v before %v", ids[i-1], ids[i])
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		ids := ObjectIDs{expected[0], "", ObjectID("123"), expected[1]}
+		sort.Sort(ids)
+		if ids[0] != "" || ids[1] != ObjectID("123") {
+			t.Fatalf("expected short ids first, got %v", ids)
+		}
+	})
+}
+
+func TestInSet(t *testing.T) {
+	id, _ := ObjectIDHex(testID)
+	other, _ := ObjectIDHex("5d6f6ff1646327ce31968d94")
+	set := NewObjectIDSet(id, NewObjectID())
+
+	t.Run("present", func(t *testing.T) {
+		if !id.InSet(set) {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		if other.InSet(set) || ObjectID("").InSet(set) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("nil_set", func(t *testing.T) {
+		if id.InSet(nil) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestAllSameSecond(t *testing.T) {
+	now := time.Now()
+	fromTime := func(ts time.Time) ObjectID {
+		id, _ := ObjectIDHex(primitive.NewObjectIDFromTimestamp(ts).Hex())
+		return id
+	}
+
+	t.Run("same_second", func(t *testing.T) {
+		ids := []ObjectID{fromTime(now), fromTime(now), fromTime(now)}
+		if !AllSameSecond(ids) {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("cross_second", func(t *testing.T) {
+		ids := []ObjectID{fromTime(now), fromTime(now.Add(time.Second))}
+		if AllSameSecond(ids) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ids := []ObjectID{fromTime(now), ObjectID("123")}
+		if AllSameSecond(ids) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestKMergeSorted(t *testing.T) {
+	ids := make(ObjectIDs, 7)
+	for i := range ids {
+		ids[i], _ = ObjectIDHex(fmt.Sprintf("5d6f6ff1646327ce31968d9%d", i))
+	}
+
+	a := ObjectIDs{ids[0], ids[3], ids[5]}
+	b := ObjectIDs{ids[1], ids[3], ids[6]}
+	c := ObjectIDs{ids[2], ids[4], ids[5]}
+
+	t.Run("three_streams", func(t *testing.T) {
+		out := KMergeSorted(a, b, nil, c)
+		expected := ObjectIDs{ids[0], ids[1], ids[2], ids[3], ids[3], ids[4], ids[5], ids[5], ids[6]}
+		if !reflect.DeepEqual(expected, out) {
+			t.Fatalf("\nexpected: %v \n got %v", expected, out)
+		}
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		out := KMergeSortedUnique(a, b, c)
+		if !reflect.DeepEqual(ids, out) {
+			t.Fatalf("\nexpected: %v \n got %v", ids, out)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if out := KMergeSorted(); len(out) != 0 {
+			t.Fatalf("expected empty result, got %v", out)
+		}
+	})
+}
+
+func TestLongestSameSecondRun(t *testing.T) {
+	mustHex := func(s string) ObjectID {
+		id, err := ObjectIDHex(s)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+		return id
+	}
+
+	ids := ObjectIDs{
+		mustHex("5d6f6ff0646327ce31968d90"),
+		mustHex("5d6f6ff1646327ce31968d91"),
+		mustHex("5d6f6ff1646327ce31968d92"),
+		mustHex("5d6f6ff1646327ce31968d93"),
+		mustHex("5d6f6ff1646327ce31968d94"),
+		mustHex("5d6f6ff2646327ce31968d95"),
+		mustHex("5d6f6ff2646327ce31968d96"),
+	}
+
+	if n := LongestSameSecondRun(ids); n != 4 {
+		t.Fatalf("expected 4, got %d", n)
+	}
+
+	broken := append(ObjectIDs{}, ids[:3]...)
+	broken = append(broken, ObjectID("123"))
+	broken = append(broken, ids[3:]...)
+	if n := LongestSameSecondRun(broken); n != 2 {
+		t.Fatalf("expected 2, got %d", n)
+	}
+
+	if n := LongestSameSecondRun(nil); n != 0 {
+		t.Fatalf("expected 0, got %d", n)
+	}
+}
+
+func TestSortedEntries(t *testing.T) {
+	m := map[ObjectID]string{}
+	var expected []Entry[string]
+	for i := 0; i < 10; i++ {
+		id, _ := ObjectIDHex(fmt.Sprintf("5d6f6ff1646327ce31968d%02x", i))
+		m[id] = fmt.Sprint(i)
+		expected = append(expected, Entry[string]{ID: id, Val: fmt.Sprint(i)})
+	}
+
+	for run := 0; run < 5; run++ {
+		if out := SortedEntries(m); !reflect.DeepEqual(expected, out) {
+			t.Fatalf("\nexpected: %v \n got %v", expected, out)
+		}
+	}
+}
+
+func TestEstimateRate(t *testing.T) {
+	base := time.Unix(testIDSecs, 0)
+	fromTime := func(ts time.Time) ObjectID {
+		id, _ := ObjectIDHex(primitive.NewObjectIDFromTimestamp(ts).Hex())
+		return id
+	}
+
+	var ids ObjectIDs
+	for i := 0; i < 10; i++ {
+		ids = append(ids, fromTime(base.Add(time.Duration(i/2)*time.Second)))
+	}
+	ids = append(ids, fromTime(base.Add(5*time.Second)))
+
+	t.Run("known_interval", func(t *testing.T) {
+		rate, ok := EstimateRate(ids)
+		if !ok {
+			t.Fatalf("expected ok")
+		}
+		if rate != 2.2 {
+			t.Fatalf("expected 2.2, got %v", rate)
+		}
+	})
+
+	t.Run("zero_span", func(t *testing.T) {
+		if _, ok := EstimateRate(ids[:2]); ok {
+			t.Fatalf("expected ok to be false")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, ok := EstimateRate(append(ObjectIDs{ObjectID("123")}, ids...)); ok {
+			t.Fatalf("expected ok to be false")
+		}
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Unix(testIDSecs, 0)
+	fromTime := func(ts time.Time) ObjectID {
+		id, _ := ObjectIDHex(primitive.NewObjectIDFromTimestamp(ts).Hex())
+		return id
+	}
+
+	first := fromTime(base.Add(time.Minute))
+	ids := ObjectIDs{
+		first,
+		fromTime(base),
+		ObjectID("123"),
+		first,
+		fromTime(base.Add(time.Hour)),
+	}
+
+	expected := Summary{
+		Count:       5,
+		UniqueCount: 3,
+		MinTime:     base,
+		MaxTime:     base.Add(time.Hour),
+		Invalid:     1,
+	}
+	if s := Summarize(ids); !reflect.DeepEqual(expected, s) {
+		t.Fatalf("\nexpected: %+v \n got %+v", expected, s)
+	}
+
+	if s := Summarize(nil); !reflect.DeepEqual(Summary{}, s) {
+		t.Fatalf("expected empty summary, got %+v", s)
+	}
+}
+
+func TestWithTimes(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	later, err := id.WithTimestamp(time.Unix(testIDSecs+60, 0))
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	out := ObjectIDs{id, ObjectID("123"), later}.WithTimes()
+	expected := []struct {
+		ID        ObjectID
+		CreatedAt time.Time
+	}{
+		{id, time.Unix(testIDSecs, 0)},
+		{ObjectID("123"), time.Time{}},
+		{later, time.Unix(testIDSecs+60, 0)},
+	}
+	if !reflect.DeepEqual(expected, out) {
+		t.Fatalf("\nexpected: %v \n got %v", expected, out)
+	}
+
+	if out := ObjectIDs(nil).WithTimes(); len(out) != 0 {
+		t.Fatalf("expected no pairs, got %v", out)
+	}
+}
@@ -0,0 +1,16 @@
+// Package oidotel annotates OpenTelemetry spans with ObjectIDs. It is a
+// separate module from package oid so that only code recording spans has
+// OpenTelemetry in its module graph.
+package oidotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	oid "objectid-go"
+)
+
+// Attribute returns a span attribute holding the bare hex of id under key, so
+// ids appear on spans without the ObjectID("...") wrapper of String.
+func Attribute(key string, id oid.ObjectID) attribute.KeyValue {
+	return attribute.String(key, id.Hex())
+}
@@ -0,0 +1,25 @@
+package oidotel
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	oid "objectid-go"
+)
+
+func TestAttribute(t *testing.T) {
+	hex := "5d6f6ff1646327ce31968d93"
+	id, err := oid.ObjectIDHex(hex)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	kv := Attribute("document.id", id)
+	if kv.Key != "document.id" {
+		t.Fatalf("expected document.id, got %s", kv.Key)
+	}
+	if kv.Value.Type() != attribute.STRING || kv.Value.AsString() != hex {
+		t.Fatalf("expected %s, got %v", hex, kv.Value.Emit())
+	}
+}
@@ -0,0 +1,20 @@
+// Package oidtest provides helpers for writing tests against code that uses
+// oid.ObjectID. It is kept separate from package oid so that test fixtures do
+// not end up in production builds.
+package oidtest
+
+import (
+	"encoding/binary"
+
+	oid "objectid-go"
+)
+
+// TestObjectID returns a valid ObjectID derived deterministically from n, so
+// fixtures can refer to "id number 5" and compare predictably. The timestamp
+// is zero and n is stored big-endian in the trailing bytes, which places any n
+// below 1<<24 entirely in the counter.
+func TestObjectID(n int) oid.ObjectID {
+	var b [12]byte
+	binary.BigEndian.PutUint64(b[4:], uint64(n))
+	return oid.ObjectID(b[:])
+}
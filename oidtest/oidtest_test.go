@@ -0,0 +1,26 @@
+package oidtest
+
+import "testing"
+
+func TestTestObjectID(t *testing.T) {
+	if TestObjectID(5) != TestObjectID(5) {
+		t.Fatalf("expected TestObjectID(5) to be stable")
+	}
+
+	if TestObjectID(5) == TestObjectID(6) {
+		t.Fatalf("expected TestObjectID(5) and TestObjectID(6) to differ")
+	}
+
+	id := TestObjectID(5)
+	if !id.Valid() {
+		t.Fatalf("expected valid, got %v", id)
+	}
+
+	expected := "000000000000000000000005"
+	if id.Hex() != expected {
+		t.Fatalf("expected %s, got %s", expected, id.Hex())
+	}
+	if id.Counter() != 5 {
+		t.Fatalf("expected counter 5, got %d", id.Counter())
+	}
+}
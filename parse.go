@@ -0,0 +1,246 @@
+package oid
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// shellLiteral matches an ObjectId("...") literal as printed by the mongo shell.
+// The trailing d of ObjectId is matched in either case so the String form of
+// this package is recognised as well.
+var shellLiteral = regexp.MustCompile(`ObjectI[dD]\("([^"]*)"\)`)
+
+// ParseShellLiterals finds every ObjectId("<hex>") literal in s, such as those
+// found in exported mongo shell scripts, and parses its hex. It returns the ids
+// that parsed successfully in the order they appear, along with an error for
+// each literal that did not.
+func ParseShellLiterals(s string) (ObjectIDs, []error) {
+	var ids ObjectIDs
+	var errs []error
+	for _, m := range shellLiteral.FindAllStringSubmatch(s, -1) {
+		id, err := ObjectIDHex(m[1])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid literal %s: %s", m[0], err))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, errs
+}
+
+// ParseObjectID parses an id in any of the forms it is commonly copied in: bare
+// 24 character hex, the ObjectID("<hex>") form printed by String (or the
+// ObjectId("<hex>") form of the mongo shell), and extended JSON
+// {"$oid":"<hex>"}. Surrounding whitespace is ignored. Input in none of these
+// forms returns an error quoting it. On any error the returned id is empty.
+func ParseObjectID(s string) (ObjectID, error) {
+	s = strings.TrimSpace(s)
+	if m := shellLiteral.FindStringSubmatch(s); m != nil && m[0] == s {
+		return parseHex(m[1])
+	}
+	if strings.HasPrefix(s, "{") {
+		var ext struct {
+			OID *string `json:"$oid"`
+		}
+		if err := json.Unmarshal([]byte(s), &ext); err != nil || ext.OID == nil {
+			return "", fmt.Errorf("unrecognized ObjectID format: %q", s)
+		}
+		return parseHex(*ext.OID)
+	}
+	if len(s) == 24 {
+		return parseHex(s)
+	}
+	return "", fmt.Errorf("unrecognized ObjectID format: %q", s)
+}
+
+// parseHex is ObjectIDHex without the partial id it returns alongside an error.
+func parseHex(s string) (ObjectID, error) {
+	id, err := ObjectIDHex(s)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// CanonicalKey validates an externally supplied id and returns its canonical
+// form, 24 lowercase hex characters, for use as a storage key. s may be hex in
+// either case or wrapped in the ObjectId("<hex>") or ObjectID("<hex>") form.
+// Anything else returns an error.
+func CanonicalKey(s string) (string, error) {
+	if m := shellLiteral.FindStringSubmatch(s); m != nil && m[0] == s {
+		s = m[1]
+	}
+	id, err := ObjectIDHex(s)
+	if err != nil {
+		return "", err
+	}
+	return id.Hex(), nil
+}
+
+// ObjectIDsFromEnv parses the comma separated list of hex ids held in the
+// environment variable key. Entries are trimmed and blank entries are skipped.
+// An unset or empty variable returns an empty slice. The first entry that fails
+// to parse returns an error naming the variable and the entry's position.
+func ObjectIDsFromEnv(key string) (ObjectIDs, error) {
+	ids := ObjectIDs{}
+	v := os.Getenv(key)
+	if strings.TrimSpace(v) == "" {
+		return ids, nil
+	}
+
+	for i, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		id, err := ObjectIDHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: entry %d: %s", key, i, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ReadObjectIDsFromLines reads a list of hex ids, one per line, such as those
+// dumped by ops scripts. Lines are trimmed, and blank lines and lines starting
+// with # are skipped. It returns the ids that parsed successfully in order,
+// along with an error naming the line number of each line that did not. An
+// error reading r is returned as the last error.
+func ReadObjectIDsFromLines(r io.Reader) (ObjectIDs, []error) {
+	var ids ObjectIDs
+	var errs []error
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		id, err := ObjectIDHex(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %s", n, err))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if err := sc.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return ids, errs
+}
+
+// Errors wrapped by ValidateAPIObjectID, so API handlers can tell the failure
+// categories apart with errors.Is.
+var (
+	ErrWrongLength = errors.New("wrong length for ObjectID")
+	ErrNonHex      = errors.New("non-hex character in ObjectID")
+	ErrUppercase   = errors.New("uppercase hex in ObjectID")
+)
+
+// ValidateAPIObjectID returns nil if s is exactly 24 lowercase hex characters,
+// the only form accepted by strict public APIs. Otherwise the returned error
+// wraps ErrWrongLength, ErrNonHex or ErrUppercase and describes the offending
+// input.
+func ValidateAPIObjectID(s string) error {
+	if len(s) != 24 {
+		return fmt.Errorf("%w: got %d characters, expected 24", ErrWrongLength, len(s))
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9', 'a' <= c && c <= 'f':
+		case 'A' <= c && c <= 'F':
+			return fmt.Errorf("%w: %q at position %d", ErrUppercase, c, i)
+		default:
+			return fmt.Errorf("%w: %q at position %d", ErrNonHex, c, i)
+		}
+	}
+	return nil
+}
+
+// JSONValueKind reports the form of an already decoded JSON value, following
+// the same dispatch as UnmarshalJSON: "hex" for a string, "extjson" for an
+// object holding a string under $oid or, failing a $oid key, under the legacy
+// id key, and "unknown" otherwise. It does not validate the hex itself.
+func JSONValueKind(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return "hex"
+	case map[string]interface{}:
+		oid, ok := v["$oid"]
+		if !ok {
+			oid = v["id"]
+		}
+		if _, ok := oid.(string); ok {
+			return "extjson"
+		}
+	}
+	return "unknown"
+}
+
+// ObjectIDsFromMixedJSON parses a JSON array whose elements are either hex id
+// strings or integer Unix timestamps in seconds. A timestamp is converted to
+// the boundary id for that second given by MinObjectIDForTime, so it sorts
+// before every id generated in that second. The first element that is neither
+// returns an error naming its index.
+func ObjectIDsFromMixedJSON(b []byte) (ObjectIDs, error) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(b, &elems); err != nil {
+		return nil, err
+	}
+
+	ids := make(ObjectIDs, len(elems))
+	for i, e := range elems {
+		var s string
+		if err := json.Unmarshal(e, &s); err == nil {
+			id, err := ObjectIDHex(s)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %s", i, err)
+			}
+			ids[i] = id
+			continue
+		}
+
+		var secs int64
+		if err := json.Unmarshal(e, &secs); err != nil || secs < 0 || secs > math.MaxUint32 {
+			return nil, fmt.Errorf("element %d: %s is neither a hex string nor a Unix timestamp", i, e)
+		}
+		ids[i] = MinObjectIDForTime(time.Unix(secs, 0))
+	}
+	return ids, nil
+}
+
+// ParseFixedWidthHex parses an id read from a fixed-width record, where the
+// 24 character hex may be followed by space padding. Trailing spaces are
+// trimmed before parsing, and a field made only of spaces is treated as an
+// unset field and returns an empty id.
+func ParseFixedWidthHex(field string) (ObjectID, error) {
+	s := strings.TrimRight(field, " ")
+	if s == "" {
+		return "", nil
+	}
+	return ObjectIDHex(s)
+}
+
+// ObjectIDFromHexParts reassembles an id exported as two columns: the 8
+// character hex of the timestamp and the 16 character hex of the remaining
+// bytes. It returns an error if either part has the wrong length or the
+// combined hex does not parse.
+func ObjectIDFromHexParts(timeHex, suffixHex string) (ObjectID, error) {
+	if len(timeHex) != 8 {
+		return "", fmt.Errorf("invalid time part %q: expected 8 hex characters, got %d", timeHex, len(timeHex))
+	}
+	if len(suffixHex) != 16 {
+		return "", fmt.Errorf("invalid suffix part %q: expected 16 hex characters, got %d", suffixHex, len(suffixHex))
+	}
+	return ObjectIDHex(timeHex + suffixHex)
+}
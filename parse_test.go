@@ -0,0 +1,426 @@
+package oid
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseShellLiterals(t *testing.T) {
+	other := "5d6f6ff1646327ce31968d94"
+	script := `db.users.find({_id: ObjectId("` + testID + `")})
+db.users.update({_id: ObjectID("` + other + `")}, {$set: {owner: ObjectId("1234")}})
+db.users.remove({})`
+
+	ids, errs := ParseShellLiterals(script)
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 ids, got %v", ids)
+	}
+	if ids[0].Hex() != testID || ids[1].Hex() != other {
+		t.Fatalf("expected [%s %s], got [%s %s]", testID, other, ids[0].Hex(), ids[1].Hex())
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	expected := `invalid literal ObjectId("1234"): invalid input to ObjectIDHex: "1234": decoded length was 2 bytes (expected 12)`
+	if errs[0].Error() != expected {
+		t.Fatalf("expected %s, got %s", expected, errs[0])
+	}
+}
+
+func TestParseObjectID(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"hex", testID},
+		{"uppercase", strings.ToUpper(testID)},
+		{"string_form", id.String()},
+		{"shell_form", `ObjectId("` + testID + `")`},
+		{"extended_JSON", `{"$oid":"` + testID + `"}`},
+		{"extended_JSON_spaced", ` { "$oid" : "` + testID + `" } `},
+		{"whitespace", "  " + testID + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ParseObjectID(tt.in)
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != id {
+				t.Fatalf("expected %v, got %v", id, out)
+			}
+		})
+	}
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := []struct {
+			in       string
+			expected string
+		}{
+			{"", `unrecognized ObjectID format: ""`},
+			{"1234", `unrecognized ObjectID format: "1234"`},
+			{"xxxxxxxxxxxxxxxxxxxxxxxx", `invalid input to ObjectIDHex: "xxxxxxxxxxxxxxxxxxxxxxxx"`},
+			{`ObjectID("1234")`, `invalid input to ObjectIDHex: "1234": decoded length was 2 bytes (expected 12)`},
+			{`{"id":"` + testID + `"}`, `unrecognized ObjectID format: "{\"id\":\"` + testID + `\"}"`},
+			{`{"$oid":12}`, `unrecognized ObjectID format: "{\"$oid\":12}"`},
+			{`{"$oid":"zz"}`, `invalid input to ObjectIDHex: "zz"`},
+		}
+		for _, tt := range tests {
+			out, err := ParseObjectID(tt.in)
+			if err == nil || err.Error() != tt.expected {
+				t.Fatalf("expected %s, got %v", tt.expected, err)
+			}
+			if out != "" {
+				t.Fatalf("expected empty id, got %v", out)
+			}
+		}
+	})
+}
+
+func TestCanonicalKey(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"hex", testID},
+		{"uppercase", strings.ToUpper(testID)},
+		{"wrapped", `ObjectId("` + testID + `")`},
+		{"wrapped_string_form", `ObjectID("` + strings.ToUpper(testID) + `")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := CanonicalKey(tt.in)
+			if err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if key != testID {
+				t.Fatalf("expected %s, got %s", testID, key)
+			}
+		})
+	}
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := []struct {
+			in       string
+			expected string
+		}{
+			{"", `invalid input to ObjectIDHex: "": decoded length was 0 bytes (expected 12)`},
+			{"zzz", `invalid input to ObjectIDHex: "zzz"`},
+			{`ObjectId("1234")`, `invalid input to ObjectIDHex: "1234": decoded length was 2 bytes (expected 12)`},
+			{`x ObjectId("` + testID + `")`, `invalid input to ObjectIDHex: "x ObjectId(\"` + testID + `\")"`},
+		}
+		for _, tt := range tests {
+			key, err := CanonicalKey(tt.in)
+			if err == nil || err.Error() != tt.expected {
+				t.Fatalf("expected %s, got %v", tt.expected, err)
+			}
+			if key != "" {
+				t.Fatalf("expected empty key, got %s", key)
+			}
+		}
+	})
+}
+
+func TestObjectIDsFromEnv(t *testing.T) {
+	const key = "OID_TEST_SEED_IDS"
+	other := "5d6f6ff1646327ce31968d94"
+
+	t.Run("set", func(t *testing.T) {
+		t.Setenv(key, " "+testID+" ,"+other+",")
+
+		ids, err := ObjectIDsFromEnv(key)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(ids) != 2 || ids[0].Hex() != testID || ids[1].Hex() != other {
+			t.Fatalf("expected [%s %s], got %v", testID, other, ids)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		ids, err := ObjectIDsFromEnv(key)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if ids == nil || len(ids) != 0 {
+			t.Fatalf("expected empty slice, got %v", ids)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Setenv(key, testID+",1234")
+
+		expected := `OID_TEST_SEED_IDS: entry 1: invalid input to ObjectIDHex: "1234": decoded length was 2 bytes (expected 12)`
+		if _, err := ObjectIDsFromEnv(key); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestValidateAPIObjectID(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		if err := ValidateAPIObjectID(testID); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		in       string
+		target   error
+		expected string
+	}{
+		{
+			name:     "wrong_length",
+			in:       "1234",
+			target:   ErrWrongLength,
+			expected: "wrong length for ObjectID: got 4 characters, expected 24",
+		},
+		{
+			name:     "non_hex",
+			in:       "5d6f6ff1646327ce31968d9x",
+			target:   ErrNonHex,
+			expected: "non-hex character in ObjectID: 'x' at position 23",
+		},
+		{
+			name:     "uppercase",
+			in:       strings.ToUpper(testID),
+			target:   ErrUppercase,
+			expected: "uppercase hex in ObjectID: 'D' at position 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAPIObjectID(tt.in)
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("expected %v, got %v", tt.target, err)
+			}
+			if err.Error() != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestJSONValueKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		expected string
+	}{
+		{name: "hex", in: `"` + testID + `"`, expected: "hex"},
+		{name: "extjson", in: `{"$oid":"` + testID + `"}`, expected: "extjson"},
+		{name: "extjson_non_string", in: `{"$oid":123}`, expected: "unknown"},
+		{name: "id", in: `{"id":"` + testID + `"}`, expected: "extjson"},
+		{name: "id_non_string", in: `{"id":123}`, expected: "unknown"},
+		{name: "oid_precedence", in: `{"$oid":123,"id":"` + testID + `"}`, expected: "unknown"},
+		{name: "other_object", in: `{"fail":"` + testID + `"}`, expected: "unknown"},
+		{name: "number", in: `123`, expected: "unknown"},
+		{name: "null", in: `null`, expected: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(tt.in), &v); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if kind := JSONValueKind(v); kind != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, kind)
+			}
+		})
+	}
+}
+
+func TestParseFixedWidthHex(t *testing.T) {
+	t.Run("clean", func(t *testing.T) {
+		id, err := ParseFixedWidthHex(testID)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id.Hex() != testID {
+			t.Fatalf("expected %s, got %s", testID, id.Hex())
+		}
+	})
+
+	t.Run("space_padded", func(t *testing.T) {
+		id, err := ParseFixedWidthHex(testID + "    ")
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id.Hex() != testID {
+			t.Fatalf("expected %s, got %s", testID, id.Hex())
+		}
+	})
+
+	t.Run("all_spaces", func(t *testing.T) {
+		id, err := ParseFixedWidthHex(strings.Repeat(" ", 24))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id != "" {
+			t.Fatalf("expected empty id, got %v", id)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		expected := `invalid input to ObjectIDHex: "5d6f6ff16463": decoded length was 6 bytes (expected 12)`
+		if _, err := ParseFixedWidthHex("5d6f6ff16463            "); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestObjectIDFromHexParts(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDFromHexParts(testID[:8], testID[8:])
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if id.Hex() != testID {
+			t.Fatalf("expected %s, got %s", testID, id.Hex())
+		}
+	})
+
+	t.Run("short_time", func(t *testing.T) {
+		expected := `invalid time part "5d6f6f": expected 8 hex characters, got 6`
+		if _, err := ObjectIDFromHexParts(testID[:6], testID[8:]); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("long_time", func(t *testing.T) {
+		expected := `invalid time part "5d6f6ff164": expected 8 hex characters, got 10`
+		if _, err := ObjectIDFromHexParts(testID[:10], testID[8:]); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("short_suffix", func(t *testing.T) {
+		expected := `invalid suffix part "646327ce31968d": expected 16 hex characters, got 14`
+		if _, err := ObjectIDFromHexParts(testID[:8], testID[8:22]); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("long_suffix", func(t *testing.T) {
+		expected := `invalid suffix part "646327ce31968d9300": expected 16 hex characters, got 18`
+		if _, err := ObjectIDFromHexParts(testID[:8], testID[8:]+"00"); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("non_hex", func(t *testing.T) {
+		expected := `invalid input to ObjectIDHex: "5d6f6ff1646327ce31968d9x"`
+		if _, err := ObjectIDFromHexParts(testID[:8], "646327ce31968d9x"); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
+
+func TestObjectIDsFromMixedJSON(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("mixed", func(t *testing.T) {
+		ids, err := ObjectIDsFromMixedJSON([]byte(`["` + testID + `", 1567584241, 0]`))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		boundary, _ := ObjectIDHex("5d6f6ff10000000000000000")
+		epoch, _ := ObjectIDHex("000000000000000000000000")
+		expected := ObjectIDs{id, boundary, epoch}
+		if len(ids) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, ids)
+		}
+		for i := range expected {
+			if ids[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, ids)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		ids, err := ObjectIDsFromMixedJSON([]byte(`[]`))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(ids) != 0 {
+			t.Fatalf("expected no ids, got %v", ids)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			in       string
+			expected string
+		}{
+			{"bad_hex", `["123"]`, `element 0: invalid input to ObjectIDHex: "123"`},
+			{"float", `["` + testID + `", 1.5]`, `element 1: 1.5 is neither a hex string nor a Unix timestamp`},
+			{"negative", `[-1]`, `element 0: -1 is neither a hex string nor a Unix timestamp`},
+			{"object", `[{"$oid":"` + testID + `"}]`, `element 0: {"$oid":"` + testID + `"} is neither a hex string nor a Unix timestamp`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := ObjectIDsFromMixedJSON([]byte(tt.in))
+				if err == nil || err.Error() != tt.expected {
+					t.Fatalf("expected %s, got %v", tt.expected, err)
+				}
+			})
+		}
+	})
+}
+
+func TestReadObjectIDsFromLines(t *testing.T) {
+	other := "5d6f6ff1646327ce31968d94"
+	in := "# ids to backfill\n" +
+		testID + "\n" +
+		"\n" +
+		"   " + other + "  \r\n" +
+		"  # " + testID + "\n" +
+		"zzz\n" +
+		"\t\n" +
+		testID
+
+	ids, errs := ReadObjectIDsFromLines(strings.NewReader(in))
+	if len(ids) != 3 || ids[0].Hex() != testID || ids[1].Hex() != other || ids[2].Hex() != testID {
+		t.Fatalf("expected [%s %s %s], got %v", testID, other, testID, ids)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	expected := `line 6: invalid input to ObjectIDHex: "zzz"`
+	if errs[0].Error() != expected {
+		t.Fatalf("expected %s, got %s", expected, errs[0])
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		ids, errs := ReadObjectIDsFromLines(strings.NewReader(""))
+		if len(ids) != 0 || len(errs) != 0 {
+			t.Fatalf("expected nothing, got %v and %v", ids, errs)
+		}
+	})
+
+	t.Run("read_error", func(t *testing.T) {
+		r := io.MultiReader(strings.NewReader(testID+"\n"), iotest.ErrReader(errors.New("boom")))
+		ids, errs := ReadObjectIDsFromLines(r)
+		if len(ids) != 1 || len(errs) != 1 || errs[0].Error() != "boom" {
+			t.Fatalf("expected one id and the read error, got %v and %v", ids, errs)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package oid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimeWindowsOrFilter returns a filter matching documents whose field holds an
+// ObjectID created within any of the given [start, end) windows. Each window
+// becomes a $gte/$lt range on the boundary ids for its start and end, and the
+// ranges are combined with $or. Windows may overlap; the caller is responsible
+// for any deduplication of the windows themselves.
+func TimeWindowsOrFilter(field string, windows [][2]time.Time) bson.M {
+	ranges := make([]bson.M, 0, len(windows))
+	for _, w := range windows {
+		ranges = append(ranges, bson.M{field: bson.M{
+			"$gte": MinObjectIDForTime(w[0]),
+			"$lt":  MinObjectIDForTime(w[1]),
+		}})
+	}
+	return bson.M{"$or": ranges}
+}
+
+// IDOnlyProjection returns a projection that only includes _id, for existence
+// and ordering queries that do not need the rest of the document.
+func IDOnlyProjection() bson.M {
+	return bson.M{"_id": 1}
+}
+
+// DecodeIDOnly collects the _id of every document remaining in cur and closes
+// it. Only the _id field is decoded, so it pairs with IDOnlyProjection but also
+// works on cursors over full documents.
+func DecodeIDOnly(ctx context.Context, cur *mongo.Cursor) (ObjectIDs, error) {
+	defer cur.Close(ctx)
+
+	var ids ObjectIDs
+	for cur.Next(ctx) {
+		var doc struct {
+			ID ObjectID `bson:"_id"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids, cur.Err()
+}
+
+// ReplaceModels pairs each id with the document at the same index and returns
+// upserting ReplaceOneModels filtered on _id, ready for BulkWrite. It returns an
+// error if the lengths differ or any id is invalid.
+func ReplaceModels(ids ObjectIDs, docs []interface{}) ([]mongo.WriteModel, error) {
+	if len(ids) != len(docs) {
+		return nil, fmt.Errorf("got %d ids but %d documents", len(ids), len(docs))
+	}
+
+	models := make([]mongo.WriteModel, len(ids))
+	for i, id := range ids {
+		p, err := id.ToPrimitive()
+		if err != nil {
+			return nil, fmt.Errorf("invalid id at index %d: %w", i, err)
+		}
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": p}).
+			SetReplacement(docs[i]).
+			SetUpsert(true)
+	}
+	return models, nil
+}
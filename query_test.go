@@ -0,0 +1,138 @@
+package oid
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestTimeWindowsOrFilter(t *testing.T) {
+	tearUp(t, func(ctx context.Context, e *mongo.Collection) {
+		base := time.Unix(testIDSecs, 0)
+		for _, h := range []int{0, 1, 2, 3, 4, 5} {
+			doc := bson.M{"_id": primitive.NewObjectIDFromTimestamp(base.Add(time.Duration(h) * time.Hour)), "h": h}
+			if _, err := e.InsertOne(ctx, doc); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+		}
+
+		filter := TimeWindowsOrFilter("_id", [][2]time.Time{
+			{base, base.Add(2 * time.Hour)},
+			{base.Add(4 * time.Hour), base.Add(5 * time.Hour)},
+		})
+
+		cur, err := e.Find(ctx, filter)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		var out []struct {
+			H int `bson:"h"`
+		}
+		if err := cur.All(ctx, &out); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		hours := map[int]bool{}
+		for _, o := range out {
+			hours[o.H] = true
+		}
+		if len(out) != 3 || !hours[0] || !hours[1] || !hours[4] {
+			t.Fatalf("expected hours 0, 1 and 4, got %+v", out)
+		}
+	})
+}
+
+func TestDecodeIDOnly(t *testing.T) {
+	tearUp(t, func(ctx context.Context, e *mongo.Collection) {
+		var expected ObjectIDs
+		for i := 0; i < 3; i++ {
+			id := NewObjectID()
+			expected = append(expected, id)
+			if _, err := e.InsertOne(ctx, bson.M{"_id": id, "n": i}); err != nil {
+				t.Fatalf("expected nil, got %s", err)
+			}
+		}
+
+		opts := options.Find().SetProjection(IDOnlyProjection()).SetSort(bson.M{"n": 1})
+		cur, err := e.Find(ctx, bson.M{}, opts)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+
+		ids, err := DecodeIDOnly(ctx, cur)
+		if err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+		if !reflect.DeepEqual(expected, ids) {
+			t.Fatalf("\nexpected: %v \n got %v", expected, ids)
+		}
+	})
+}
+
+func TestReplaceModels(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	other := NewObjectID()
+
+	t.Run("valid", func(t *testing.T) {
+		docs := []interface{}{bson.M{"n": 1}, bson.M{"n": 2}}
+		models, err := ReplaceModels(ObjectIDs{id, other}, docs)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(models) != 2 {
+			t.Fatalf("expected 2 models, got %d", len(models))
+		}
+
+		for i, want := range []ObjectID{id, other} {
+			m, ok := models[i].(*mongo.ReplaceOneModel)
+			if !ok {
+				t.Fatalf("expected *mongo.ReplaceOneModel, got %T", models[i])
+			}
+			p, _ := want.ToPrimitive()
+			if !reflect.DeepEqual(m.Filter, bson.M{"_id": p}) {
+				t.Fatalf("expected filter on %v, got %v", p, m.Filter)
+			}
+			if !reflect.DeepEqual(m.Replacement, docs[i]) {
+				t.Fatalf("expected %v, got %v", docs[i], m.Replacement)
+			}
+			if m.Upsert == nil || !*m.Upsert {
+				t.Fatalf("expected upsert, got %v", m.Upsert)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		models, err := ReplaceModels(nil, nil)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if len(models) != 0 {
+			t.Fatalf("expected no models, got %v", models)
+		}
+	})
+
+	t.Run("length_mismatch", func(t *testing.T) {
+		expected := "got 2 ids but 1 documents"
+		if _, err := ReplaceModels(ObjectIDs{id, other}, []interface{}{bson.M{}}); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		expected := "invalid id at index 1: ObjectID(\"313233\") is not an ObjectID"
+		_, err := ReplaceModels(ObjectIDs{id, "123"}, []interface{}{bson.M{}, bson.M{}})
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
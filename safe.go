@@ -0,0 +1,41 @@
+package oid
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// SafeObjectID is an ObjectID with strict JSON marshalling. Where ObjectID
+// hex-encodes whatever bytes it holds, SafeObjectID guarantees its JSON output
+// is either null, for an empty id, or a valid 24 character hex string, and
+// returns an error for anything else. BSON handling is the same as ObjectID.
+type SafeObjectID ObjectID
+
+// MarshalJSON returns null for an empty id and the quoted hex for a valid one,
+// whatever the value of JSONFormat. A malformed id returns an error instead of
+// producing a short hex string.
+func (id SafeObjectID) MarshalJSON() ([]byte, error) {
+	if id == "" {
+		return nullBytes, nil
+	}
+	if !ObjectID(id).Valid() {
+		return nil, fmt.Errorf("%s is not an ObjectID", ObjectID(id).String())
+	}
+	return []byte("\"" + ObjectID(id).Hex() + "\""), nil
+}
+
+// UnmarshalJSON behaves like ObjectID.UnmarshalJSON.
+func (id *SafeObjectID) UnmarshalJSON(b []byte) error {
+	return (*ObjectID)(id).UnmarshalJSON(b)
+}
+
+// MarshalBSONValue behaves like ObjectID.MarshalBSONValue.
+func (id SafeObjectID) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return ObjectID(id).MarshalBSONValue()
+}
+
+// UnmarshalBSONValue behaves like ObjectID.UnmarshalBSONValue.
+func (id *SafeObjectID) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	return (*ObjectID)(id).UnmarshalBSONValue(t, b)
+}
@@ -0,0 +1,81 @@
+package oid
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSafeObjectIDJSON(t *testing.T) {
+	type safe struct {
+		V SafeObjectID
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		b, err := json.Marshal(safe{V: SafeObjectID(id)})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		expected := `{"V":"` + testID + `"}`
+		if string(b) != expected {
+			t.Fatalf("expected %s, got %s", expected, b)
+		}
+
+		var out safe
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.V != SafeObjectID(id) {
+			t.Fatalf("expected %v, got %v", id, out.V)
+		}
+	})
+
+	t.Run("ignores_JSONFormat", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+		JSONFormat = JSONExtended
+		defer func() { JSONFormat = JSONHex }()
+
+		b, err := json.Marshal(safe{V: SafeObjectID(id)})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		expected := `{"V":"` + testID + `"}`
+		if string(b) != expected {
+			t.Fatalf("expected %s, got %s", expected, b)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		b, err := json.Marshal(safe{})
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		expected := `{"V":null}`
+		if string(b) != expected {
+			t.Fatalf("expected %s, got %s", expected, b)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := json.Marshal(safe{V: SafeObjectID("123")})
+		if err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+
+		expected := `ObjectID("313233") is not an ObjectID`
+		if !strings.HasSuffix(err.Error(), expected) {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package oid
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer, storing the id as its 24 character hex. An
+// empty id is stored as NULL and any other invalid id returns an error.
+func (id ObjectID) Value() (driver.Value, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if !id.Valid() {
+		return nil, fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	return id.Hex(), nil
+}
+
+// Scan implements sql.Scanner for columns holding the 24 character hex of an
+// id as a string or []byte. NULL and empty values leave the id empty. Any other
+// value returns an error and leaves the id unchanged.
+func (id *ObjectID) Scan(src interface{}) error {
+	var s string
+	switch src := src.(type) {
+	case nil:
+		*id = ""
+		return nil
+	case string:
+		s = src
+	case []byte:
+		s = string(src)
+	default:
+		return fmt.Errorf("cannot scan %T into ObjectID", src)
+	}
+
+	if s == "" {
+		*id = ""
+		return nil
+	}
+	v, err := ObjectIDHex(s)
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
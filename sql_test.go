@@ -0,0 +1,90 @@
+package oid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = ObjectID("")
+	_ sql.Scanner   = (*ObjectID)(nil)
+)
+
+func TestSQL(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		var v driver.Valuer = id
+		val, err := v.Value()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if val != testID {
+			t.Fatalf("expected %s, got %v", testID, val)
+		}
+
+		for _, src := range []interface{}{val, []byte(val.(string))} {
+			var out ObjectID
+			if err := out.Scan(src); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != id {
+				t.Fatalf("expected %v, got %v", id, out)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		val, err := ObjectID("").Value()
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if val != nil {
+			t.Fatalf("expected nil, got %v", val)
+		}
+
+		for _, src := range []interface{}{nil, "", []byte{}} {
+			out := id
+			if err := out.Scan(src); err != nil {
+				t.Fatalf("expected nil, got %v", err)
+			}
+			if out != "" {
+				t.Fatalf("expected empty id, got %v", out)
+			}
+		}
+	})
+
+	t.Run("invalid_value", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		if _, err := ObjectID("123").Value(); err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+
+	t.Run("invalid_scan", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			src      interface{}
+			expected string
+		}{
+			{"short", "55", `invalid input to ObjectIDHex: "55": decoded length was 1 byte (expected 12)`},
+			{"non_hex", []byte("xxxxxxxxxxxxxxxxxxxxxxxx"), `invalid input to ObjectIDHex: "xxxxxxxxxxxxxxxxxxxxxxxx"`},
+			{"type", 42, "cannot scan int into ObjectID"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				out := id
+				if err := out.Scan(tt.src); err == nil || err.Error() != tt.expected {
+					t.Fatalf("expected %s, got %v", tt.expected, err)
+				}
+				if out != id {
+					t.Fatalf("expected %v to be unchanged, got %v", id, out)
+				}
+			})
+		}
+	})
+}
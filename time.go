@@ -0,0 +1,169 @@
+package oid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NowFunc returns the current time for the helpers in this package that
+// compare an id's timestamp against now. It can be replaced to control the
+// clock in tests and should otherwise be left alone.
+var NowFunc = time.Now
+
+// HumanAge returns how long ago the id was created relative to NowFunc, such as
+// "3 minutes ago" or "2 days ago". Ages below a minute, including ids from the
+// future, are "just now". An invalid id returns "unknown".
+func (id ObjectID) HumanAge() string {
+	if !id.Valid() {
+		return "unknown"
+	}
+
+	age := NowFunc().Sub(id.Time())
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age/time.Minute), "minute") + " ago"
+	case age < 24*time.Hour:
+		return plural(int(age/time.Hour), "hour") + " ago"
+	}
+	return plural(int(age/(24*time.Hour)), "day") + " ago"
+}
+
+// AgeBucket returns a dashboard label for the age of the id relative to
+// NowFunc. The buckets are rolling windows: "today" is under 24 hours, "this
+// week" under 7 days, "this month" under 30 days and anything else is "older".
+// Ids from the future are "today". An invalid id returns "unknown".
+func (id ObjectID) AgeBucket() string {
+	if !id.Valid() {
+		return "unknown"
+	}
+
+	const day = 24 * time.Hour
+	age := NowFunc().Sub(id.Time())
+	switch {
+	case age < day:
+		return "today"
+	case age < 7*day:
+		return "this week"
+	case age < 30*day:
+		return "this month"
+	}
+	return "older"
+}
+
+// OlderThan reports whether the id was created before NowFunc minus retention.
+// An invalid id returns false so that a retention sweeper never deletes a
+// document it cannot date.
+func (id ObjectID) OlderThan(retention time.Duration) bool {
+	if !id.Valid() {
+		return false
+	}
+	return id.Time().Before(NowFunc().Add(-retention))
+}
+
+// MatchesTime reports whether the time embedded in the id is within tolerance
+// of t, in either direction. It is meant for audits comparing _id against a
+// separate creation time field. An invalid id returns false.
+func (id ObjectID) MatchesTime(t time.Time, tolerance time.Duration) bool {
+	if !id.Valid() {
+		return false
+	}
+	d := id.Time().Sub(t)
+	if d < 0 {
+		d = -d
+	}
+	return d <= tolerance
+}
+
+// EpochMillis returns the creation time of the id in milliseconds since the
+// Unix epoch, the convention used by JavaScript. Timestamps only have second
+// precision, so the value always ends in 000. An invalid id returns 0.
+func (id ObjectID) EpochMillis() int64 {
+	if !id.Valid() {
+		return 0
+	}
+	return id.Time().UnixMilli()
+}
+
+// TimeRoundedToMinute returns the creation time of the id in UTC, truncated
+// down to the start of its minute, for analytics exports that should not carry
+// the exact second. An invalid id returns the zero time.
+func (id ObjectID) TimeRoundedToMinute() time.Time {
+	if !id.Valid() {
+		return time.Time{}
+	}
+	return id.Time().UTC().Truncate(time.Minute)
+}
+
+// NewObjectIDWithTime returns a new unique ObjectID whose timestamp is t,
+// truncated to the second, for backfilling historical data. The remaining bytes
+// are filled as for NewObjectID. The timestamp holds seconds since the epoch as
+// an unsigned 32-bit value, covering 1970 to 2106, so times after 2038 are
+// stored as is; times outside that range are clamped to its ends instead of
+// wrapping around, which keeps them in order.
+func NewObjectIDWithTime(t time.Time) ObjectID {
+	p := primitive.NewObjectIDFromTimestamp(t)
+	binary.BigEndian.PutUint32(p[:4], clampSeconds(t))
+	return FromPrimitive(p)
+}
+
+// clampSeconds returns the seconds since the epoch of t clamped to the range of
+// an ObjectID timestamp.
+func clampSeconds(t time.Time) uint32 {
+	secs := t.Unix()
+	switch {
+	case secs < 0:
+		return 0
+	case secs > math.MaxUint32:
+		return math.MaxUint32
+	}
+	return uint32(secs)
+}
+
+// MinObjectIDForTime returns the smallest ObjectID with the timestamp of t,
+// truncated to the second: the timestamp bytes followed by eight 0x00 bytes. It
+// is the usual bound for $gte and $lt range queries on _id, as no generated id
+// from that second sorts before it. Times outside 1970 to 2106 are clamped as
+// in NewObjectIDWithTime.
+func MinObjectIDForTime(t time.Time) ObjectID {
+	var b [12]byte
+	binary.BigEndian.PutUint32(b[:4], clampSeconds(t))
+	return ObjectID(b[:])
+}
+
+// MaxObjectIDForTime returns the largest ObjectID with the timestamp of t,
+// truncated to the second: the timestamp bytes followed by eight 0xff bytes. No
+// generated id from that second sorts after it.
+func MaxObjectIDForTime(t time.Time) ObjectID {
+	b := [12]byte{4: 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	binary.BigEndian.PutUint32(b[:4], clampSeconds(t))
+	return ObjectID(b[:])
+}
+
+// WithTimestamp returns a copy of the id with its timestamp replaced by t,
+// truncated to the second, keeping the remaining 8 bytes. It is meant for
+// aging fixture ids in tests. An invalid id returns an error, as does a time
+// outside the 1970 to 2106 range of the timestamp, which would otherwise wrap.
+func (id ObjectID) WithTimestamp(t time.Time) (ObjectID, error) {
+	if !id.Valid() {
+		return "", fmt.Errorf("%s is not an ObjectID", id.String())
+	}
+	if secs := t.Unix(); secs < 0 || secs > math.MaxUint32 {
+		return "", fmt.Errorf("time %s is outside the range of an ObjectID timestamp", t.UTC().Format(time.RFC3339))
+	}
+	b := []byte(id)
+	binary.BigEndian.PutUint32(b[:4], uint32(t.Unix()))
+	return ObjectID(b), nil
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,316 @@
+package oid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// setNow pins NowFunc to t for the duration of the test.
+func setNow(tb testing.TB, t time.Time) {
+	tb.Helper()
+	NowFunc = func() time.Time { return t }
+	tb.Cleanup(func() { NowFunc = time.Now })
+}
+
+func TestHumanAge(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	created := time.Unix(testIDSecs, 0)
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected string
+	}{
+		{name: "just_now", age: 30 * time.Second, expected: "just now"},
+		{name: "future", age: -time.Hour, expected: "just now"},
+		{name: "one_minute", age: time.Minute, expected: "1 minute ago"},
+		{name: "minutes", age: 3*time.Minute + 10*time.Second, expected: "3 minutes ago"},
+		{name: "hours", age: 5 * time.Hour, expected: "5 hours ago"},
+		{name: "days", age: 49 * time.Hour, expected: "2 days ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNow(t, created.Add(tt.age))
+			if id.HumanAge() != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, id.HumanAge())
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		if ObjectID("123").HumanAge() != "unknown" {
+			t.Fatalf("expected unknown, got %s", ObjectID("123").HumanAge())
+		}
+	})
+}
+
+func TestAgeBucket(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	created := time.Unix(testIDSecs, 0)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected string
+	}{
+		{name: "future", age: -time.Hour, expected: "today"},
+		{name: "today", age: day - time.Second, expected: "today"},
+		{name: "week_start", age: day, expected: "this week"},
+		{name: "week_end", age: 7*day - time.Second, expected: "this week"},
+		{name: "month_start", age: 7 * day, expected: "this month"},
+		{name: "month_end", age: 30*day - time.Second, expected: "this month"},
+		{name: "older", age: 30 * day, expected: "older"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNow(t, created.Add(tt.age))
+			if id.AgeBucket() != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, id.AgeBucket())
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		if ObjectID("123").AgeBucket() != "unknown" {
+			t.Fatalf("expected unknown, got %s", ObjectID("123").AgeBucket())
+		}
+	})
+}
+
+func TestOlderThan(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	setNow(t, time.Unix(testIDSecs, 0).Add(48*time.Hour))
+
+	t.Run("within_retention", func(t *testing.T) {
+		if id.OlderThan(72 * time.Hour) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("past_retention", func(t *testing.T) {
+		if !id.OlderThan(24 * time.Hour) {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if ObjectID("123").OlderThan(0) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestMatchesTime(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+	created := time.Unix(testIDSecs, 0)
+
+	t.Run("within_tolerance", func(t *testing.T) {
+		if !id.MatchesTime(created.Add(1500*time.Millisecond), 2*time.Second) {
+			t.Fatalf("expected true, got false")
+		}
+		if !id.MatchesTime(created.Add(-2*time.Second), 2*time.Second) {
+			t.Fatalf("expected true, got false")
+		}
+	})
+
+	t.Run("outside_tolerance", func(t *testing.T) {
+		if id.MatchesTime(created.Add(time.Hour), time.Minute) {
+			t.Fatalf("expected false, got true")
+		}
+		if id.MatchesTime(created.Add(-time.Hour), time.Minute) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if ObjectID("123").MatchesTime(created, time.Hour) {
+			t.Fatalf("expected false, got true")
+		}
+	})
+}
+
+func TestEpochMillis(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	if id.EpochMillis() != testIDSecs*1000 {
+		t.Fatalf("expected %d, got %d", testIDSecs*1000, id.EpochMillis())
+	}
+
+	if ObjectID("123").EpochMillis() != 0 {
+		t.Fatalf("expected 0, got %d", ObjectID("123").EpochMillis())
+	}
+}
+
+func TestTimeRoundedToMinute(t *testing.T) {
+	id, err := ObjectIDHex(testID)
+	if err != nil {
+		t.Fatalf("could not make objectId %v", err)
+	}
+
+	// testID was created at 2019-09-04T08:04:01Z.
+	expected := time.Date(2019, 9, 4, 8, 4, 0, 0, time.UTC)
+	out := id.TimeRoundedToMinute()
+	if !out.Equal(expected) || out.Location() != time.UTC {
+		t.Fatalf("expected %v, got %v", expected, out)
+	}
+	if out.Second() != 0 {
+		t.Fatalf("expected 0, got %d", out.Second())
+	}
+
+	if !ObjectID("123").TimeRoundedToMinute().IsZero() {
+		t.Fatalf("expected zero time, got %v", ObjectID("123").TimeRoundedToMinute())
+	}
+}
+
+func TestNewObjectIDWithTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       time.Time
+		expected time.Time
+	}{
+		{"truncated", time.Unix(testIDSecs, 999999999), time.Unix(testIDSecs, 0)},
+		{"epoch", time.Unix(0, 0), time.Unix(0, 0)},
+		{"after_2038", time.Date(2050, 1, 2, 3, 4, 5, 0, time.UTC), time.Date(2050, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"max", time.Unix(math.MaxUint32, 0), time.Unix(math.MaxUint32, 0)},
+		{"before_epoch", time.Date(1969, 7, 20, 20, 17, 0, 0, time.UTC), time.Unix(0, 0)},
+		{"after_max", time.Unix(math.MaxUint32+1, 0), time.Unix(math.MaxUint32, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := NewObjectIDWithTime(tt.in)
+			if !id.Valid() {
+				t.Fatalf("expected a valid id, got %v", id)
+			}
+			if !id.Time().Equal(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, id.Time())
+			}
+		})
+	}
+
+	t.Run("unique", func(t *testing.T) {
+		now := time.Now()
+		a, b := NewObjectIDWithTime(now), NewObjectIDWithTime(now)
+		if a == b {
+			t.Fatalf("expected distinct ids, got %v twice", a)
+		}
+	})
+}
+
+func TestObjectIDForTimeBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       time.Time
+		min, max string
+	}{
+		{"valid", time.Unix(testIDSecs, 500), "5d6f6ff10000000000000000", "5d6f6ff1ffffffffffffffff"},
+		{"epoch", time.Unix(0, 0), "000000000000000000000000", "00000000ffffffffffffffff"},
+		{"before_epoch", time.Unix(-1, 0), "000000000000000000000000", "00000000ffffffffffffffff"},
+		{"after_max", time.Unix(math.MaxUint32+1, 0), "ffffffff0000000000000000", "ffffffffffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if h := MinObjectIDForTime(tt.in).Hex(); h != tt.min {
+				t.Fatalf("expected %s, got %s", tt.min, h)
+			}
+			if h := MaxObjectIDForTime(tt.in).Hex(); h != tt.max {
+				t.Fatalf("expected %s, got %s", tt.max, h)
+			}
+		})
+	}
+
+	t.Run("brackets", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+		if !id.Between(MinObjectIDForTime(id.Time()), MaxObjectIDForTime(id.Time())) {
+			t.Fatalf("expected %v to be within the bounds of its own second", id)
+		}
+	})
+}
+
+func TestWithTimestamp(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		older := id.Time().Add(-30 * 24 * time.Hour)
+		out, err := id.WithTimestamp(older)
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if !out.Time().Equal(older) {
+			t.Fatalf("expected %v, got %v", older, out.Time())
+		}
+		if out[4:] != id[4:] {
+			t.Fatalf("expected suffix %x, got %x", id[4:], out[4:])
+		}
+		if id.Hex() != testID {
+			t.Fatalf("expected %s to be unchanged, got %s", testID, id.Hex())
+		}
+	})
+
+	t.Run("out_of_range", func(t *testing.T) {
+		id, err := ObjectIDHex(testID)
+		if err != nil {
+			t.Fatalf("could not make objectId %v", err)
+		}
+
+		tests := []struct {
+			name     string
+			in       time.Time
+			expected string
+		}{
+			{"before_1970", time.Unix(-1, 0), "time 1969-12-31T23:59:59Z is outside the range of an ObjectID timestamp"},
+			{"after_2106", time.Unix(1<<33, 0), "time 2242-03-16T12:56:32Z is outside the range of an ObjectID timestamp"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				out, err := id.WithTimestamp(tt.in)
+				if err == nil || err.Error() != tt.expected {
+					t.Fatalf("expected %s, got %v", tt.expected, err)
+				}
+				if out != "" {
+					t.Fatalf("expected empty id, got %v", out)
+				}
+			})
+		}
+
+		out, err := id.WithTimestamp(time.Unix(math.MaxUint32, 0))
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if out.Hex() != "ffffffff"+testID[8:] {
+			t.Fatalf("expected ffffffff%s, got %s", testID[8:], out.Hex())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		expected := "ObjectID(\"313233\") is not an ObjectID"
+		_, err := ObjectID("123").WithTimestamp(time.Now())
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected %s, got %v", expected, err)
+		}
+	})
+}